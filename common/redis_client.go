@@ -1,66 +1,73 @@
 package common
 
 import (
-	"fmt"
-	"github.com/go-redis/redis/v8"
+	"errors"
 	"log"
 	"sync"
+
+	"github.com/go-redis/redis/v8"
 )
 
-var redisClient = &redis.Client{}
-var once sync.Once
+var (
+	redisClient  redis.UniversalClient
+	redisOptions = defaultRedisOptions()
+	redisOwned   bool // redisClient由NewRedisClient按配置新建，替换时需关闭
+	redisMu      sync.Mutex
+)
 
-type redisConfig struct {
-	Host     string
-	Port     int
-	Password string
+// 默认连接本机redis
+func defaultRedisOptions() *redis.UniversalOptions {
+	return &redis.UniversalOptions{
+		Addrs:        []string{"127.0.0.1:6379"},
+		Password:     "",
+		DB:           0,
+		MaxRetries:   3,
+		PoolSize:     20,
+		MinIdleConns: 4,
+	}
 }
 
-// RedisClient 根据name实例化redis对象
-func NewRedisClient() *redis.Client {
-	once.Do(func() {
-		config := redisConfig{}
-		err := getConf(&config)
-		if err != nil {
-			panic(fmt.Errorf("NewRedisClient config err:%v", err))
-		}
-		addr := fmt.Sprintf("%s:%d", config.Host, config.Port)
-		options := redis.Options{
-			Addr:               addr,
-			Dialer:             nil,
-			OnConnect:          nil,
-			Password:           config.Password,
-			DB:                 0,
-			MaxRetries:         3,
-			MinRetryBackoff:    0,
-			MaxRetryBackoff:    0,
-			DialTimeout:        0,
-			ReadTimeout:        0,
-			WriteTimeout:       0,
-			PoolSize:           20,
-			MinIdleConns:       4,
-			MaxConnAge:         0,
-			PoolTimeout:        0,
-			IdleTimeout:        0,
-			IdleCheckFrequency: 0,
-			TLSConfig:          nil,
+// SetRedisOptions 设置redis连接配置（地址、密码、DB、TLS、连接池等）
+// 多个地址为集群，设置MasterName为哨兵。已按旧配置新建的客户端会被关闭，
+// 之后的 NewRedisClient 按新配置新建
+func SetRedisOptions(options *redis.UniversalOptions) error {
+	if options == nil {
+		return errors.New("redis options is nil")
+	}
+	redisMu.Lock()
+	defer redisMu.Unlock()
+	redisOptions = options
+	replaceRedisClient(nil, false)
+	return nil
+}
+
+// SetRedisClient 注册已有的redis客户端，scron、sys_info、redis_locker共用。
+// 客户端由调用方负责关闭，传nil则恢复按配置新建
+func SetRedisClient(client redis.UniversalClient) {
+	redisMu.Lock()
+	defer redisMu.Unlock()
+	replaceRedisClient(client, false)
+}
+
+// replaceRedisClient 替换注册的客户端，旧客户端是按配置新建的则关闭其连接池，需持有redisMu
+func replaceRedisClient(client redis.UniversalClient, owned bool) {
+	if redisClient != nil && redisOwned && redisClient != client {
+		if err := redisClient.Close(); err != nil {
+			log.Println("close redisClient failed:", err)
 		}
+	}
+	redisClient = client
+	redisOwned = owned
+}
+
+// NewRedisClient 获取注册的redis客户端，未注册时按配置新建
+func NewRedisClient() redis.UniversalClient {
+	redisMu.Lock()
+	defer redisMu.Unlock()
+	if redisClient == nil {
 		// 新建一个client
-		redisClient = redis.NewClient(&options)
+		replaceRedisClient(redis.NewUniversalClient(redisOptions), true)
 		log.Println("new redisClient:", redisClient)
-	})
-	// 告警
-	if redisClient != nil {
-		return redisClient
 	}
-	// 告警
-	//alarmIns := alarm.GetAlarmInstance()
-	//alarmIns.SendAlarm(fmt.Sprintf("slp-tools.NewRedisClient错误,请及时处理！@all"), "xxxxkeyname", 5*time.Minute)
-	panic(fmt.Errorf("NewRedisClient err,redisClient:%v", redisClient))
-}
-func getConf(config *redisConfig) error {
-	config.Host = "127.0.0.1"
-	config.Port = 6379
-	config.Password = ""
-	return nil
+	return redisClient
 }
@@ -0,0 +1,87 @@
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+func resetRedis(t *testing.T) {
+	t.Cleanup(func() {
+		SetRedisClient(nil)
+		redisOptions = defaultRedisOptions()
+	})
+}
+
+func TestNewRedisClientDefault(t *testing.T) {
+	resetRedis(t)
+	client, ok := NewRedisClient().(*redis.Client)
+	if !ok {
+		t.Fatalf("expected a single node client, got %T", NewRedisClient())
+	}
+	if addr := client.Options().Addr; addr != "127.0.0.1:6379" {
+		t.Errorf("expected the local redis by default, got %s", addr)
+	}
+	if NewRedisClient() != client {
+		t.Error("expected the client to be reused")
+	}
+}
+
+func TestSetRedisOptions(t *testing.T) {
+	resetRedis(t)
+	ctx := context.Background()
+	first, second := miniredis.RunT(t), miniredis.RunT(t)
+
+	if err := SetRedisOptions(nil); err == nil {
+		t.Error("expected nil options to be rejected")
+	}
+	if err := SetRedisOptions(&redis.UniversalOptions{Addrs: []string{first.Addr()}}); err != nil {
+		t.Fatal(err)
+	}
+	old := NewRedisClient()
+	if err := old.Set(ctx, "k", "first", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetRedisOptions(&redis.UniversalOptions{Addrs: []string{second.Addr()}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := old.Ping(ctx).Err(); err != redis.ErrClosed {
+		t.Errorf("expected the replaced client closed, got %v", err)
+	}
+	if err := NewRedisClient().Set(ctx, "k", "second", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := second.Get("k"); v != "second" {
+		t.Errorf("expected the new options used, got %q", v)
+	}
+}
+
+func TestSetRedisClient(t *testing.T) {
+	resetRedis(t)
+	ctx := context.Background()
+	s := miniredis.RunT(t)
+
+	SetRedisOptions(&redis.UniversalOptions{Addrs: []string{s.Addr()}})
+	created := NewRedisClient()
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	defer client.Close()
+	SetRedisClient(client)
+	if NewRedisClient() != client {
+		t.Error("expected the registered client")
+	}
+	if err := created.Ping(ctx).Err(); err != redis.ErrClosed {
+		t.Errorf("expected the replaced client closed, got %v", err)
+	}
+
+	// A registered client belongs to the caller and is not closed.
+	SetRedisClient(nil)
+	if err := client.Ping(ctx).Err(); err != nil {
+		t.Errorf("expected the registered client left open, got %v", err)
+	}
+	if c := NewRedisClient(); c == client || c.Ping(ctx).Err() != nil {
+		t.Error("expected a new client from the options")
+	}
+}
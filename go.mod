@@ -3,17 +3,22 @@ module github.com/henryxu/tools
 go 1.20
 
 require (
+	github.com/alicebob/miniredis/v2 v2.30.4
+	github.com/go-logr/logr v1.4.2
 	github.com/go-redis/redis/v8 v8.11.5
 	github.com/shirou/gopsutil v3.21.11+incompatible
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
+	github.com/alicebob/gopher-json v0.0.0-20200520072559-a9ecdc9d1d3a // indirect
 	github.com/cespare/xxhash/v2 v2.1.2 // indirect
 	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
 	github.com/go-ole/go-ole v1.2.6 // indirect
 	github.com/stretchr/testify v1.8.4 // indirect
 	github.com/tklauser/go-sysconf v0.3.12 // indirect
 	github.com/tklauser/numcpus v0.6.1 // indirect
+	github.com/yuin/gopher-lua v1.1.0 // indirect
 	github.com/yusufpapurcu/wmi v1.2.3 // indirect
 	golang.org/x/sys v0.11.0 // indirect
 )
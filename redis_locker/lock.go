@@ -1,6 +1,7 @@
 package redis_locker
 
 import (
+	"strings"
 	"time"
 )
 
@@ -18,4 +19,24 @@ type RedisLockInter interface {
 	Renew() error
 }
 
+// FencedLock 由能签发fencing token的锁实现。token随每次加锁单调递增，下游写入时
+// 携带token并拒绝小于已见最大值的请求，即可挡住锁过期后仍在执行的旧持有者
+type FencedLock interface {
+	// FencingToken 本次加锁签发的token，未加锁时为0
+	FencingToken() int64
+}
+
+// FenceKey 返回key的fencing计数器key，与key在Redis Cluster的同一个slot。
+// 计数器不设过期时间，需比锁和任务存活更久，删除后token会从1重新开始，
+// 任务下线且下游不再校验token时才可手动删除
+func FenceKey(key string) string {
+	if i := strings.Index(key, "{"); i >= 0 {
+		if j := strings.Index(key[i+1:], "}"); j > 0 {
+			// key已有hash tag
+			return key + ":fence"
+		}
+	}
+	return "{" + key + "}:fence"
+}
+
 const lockTime = 5 * time.Second
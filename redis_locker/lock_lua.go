@@ -1,5 +1,7 @@
 package redis_locker
 
+import "github.com/go-redis/redis/v8"
+
 const (
 	// 加锁
 	lockScript = `
@@ -43,3 +45,16 @@ const (
 		return nil
 	`
 )
+
+// 加锁并签发fencing token：key不存在时设置并对计数器INCR，返回token，已存在返回0
+// 两步在同一脚本中执行，token的先后与加锁的先后一致
+var fencedLockScript = redis.NewScript(`
+	local lock_key = KEYS[1]
+	local fence_key = KEYS[2]
+	local lock_value = ARGV[1]
+	local lock_ttl = tonumber(ARGV[2])
+	if redis.call('SET', lock_key, lock_value, 'NX', 'PX', lock_ttl) then
+		return redis.call('INCR', fence_key)
+	end
+	return 0
+`)
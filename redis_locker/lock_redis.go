@@ -12,11 +12,13 @@ import (
 
 type RedisLock struct {
 	context.Context
-	*redis.Client
+	redis.UniversalClient
 	key             string
 	token           string
 	lockTimeout     time.Duration
 	isAutoRenew     bool
+	isFencing       bool
+	fence           int64
 	autoRenewCtx    context.Context
 	autoRenewCancel context.CancelFunc
 	mutex           sync.Mutex
@@ -26,11 +28,11 @@ type RedisLock struct {
 
 type Options func(lock *RedisLock)
 
-func NewRedisLocker(ctx context.Context, redisClient *redis.Client, lockKey string, options ...Options) *RedisLock {
+func NewRedisLocker(ctx context.Context, redisClient redis.UniversalClient, lockKey string, options ...Options) *RedisLock {
 	lock := &RedisLock{
-		Context:     ctx,
-		Client:      redisClient,
-		lockTimeout: lockTime,
+		Context:         ctx,
+		UniversalClient: redisClient,
+		lockTimeout:     lockTime,
 	}
 	for _, f := range options {
 		f(lock)
@@ -66,6 +68,13 @@ func WithAutoRenew() Options {
 	}
 }
 
+// WithFencing 加锁时签发fencing token，见FencingToken
+func WithFencing() Options {
+	return func(lock *RedisLock) {
+		lock.isFencing = true
+	}
+}
+
 // WithToken 设置锁的Token
 func WithToken(token string) Options {
 	return func(lock *RedisLock) {
@@ -77,9 +86,21 @@ func WithToken(token string) Options {
 func (lock *RedisLock) Lock() error {
 	lock.mutex.Lock()
 	defer lock.mutex.Unlock()
-	result, err := lock.Client.SetNX(lock.Context, lock.key, lock.token, time.Duration(lock.lockTimeout.Seconds())*time.Second).Result()
-	if !result || err != nil {
-		return errors.New("lock key failed")
+	if lock.isFencing {
+		ttl := lock.lockTimeout.Milliseconds()
+		if ttl <= 0 {
+			ttl = 1
+		}
+		fence, err := fencedLockScript.Run(lock.Context, lock.UniversalClient, []string{lock.key, FenceKey(lock.key)}, lock.token, ttl).Int64()
+		if fence == 0 || err != nil {
+			return errors.New("lock key failed")
+		}
+		lock.fence = fence
+	} else {
+		result, err := lock.UniversalClient.SetNX(lock.Context, lock.key, lock.token, time.Duration(lock.lockTimeout.Seconds())*time.Second).Result()
+		if !result || err != nil {
+			return errors.New("lock key failed")
+		}
 	}
 	if lock.isAutoRenew {
 		lock.autoRenewCtx, lock.autoRenewCancel = context.WithCancel(lock.Context)
@@ -88,6 +109,13 @@ func (lock *RedisLock) Lock() error {
 	return nil
 }
 
+// FencingToken 本次加锁签发的token，需开启WithFencing
+func (lock *RedisLock) FencingToken() int64 {
+	lock.mutex.Lock()
+	defer lock.mutex.Unlock()
+	return lock.fence
+}
+
 // UnLock 解锁
 func (lock *RedisLock) UnLock() error {
 	lock.mutex.Lock()
@@ -97,8 +125,8 @@ func (lock *RedisLock) UnLock() error {
 	if lock.autoRenewCancel != nil {
 		lock.autoRenewCancel()
 	}
-	if lock.Client.Get(lock.Context, lock.key).Val() == lock.token {
-		if err := lock.Client.Del(lock.Context, lock.key).Err(); err != nil {
+	if lock.UniversalClient.Get(lock.Context, lock.key).Val() == lock.token {
+		if err := lock.UniversalClient.Del(lock.Context, lock.key).Err(); err != nil {
 			return fmt.Errorf("failed to remove lock: %s", err)
 		}
 	} else {
@@ -147,8 +175,8 @@ func (lock *RedisLock) Renew() error {
 	//if err == redis.Nil {
 	//	return nil
 	//}
-	if lock.Client.Get(lock.Context, lock.key).Val() == lock.token {
-		if err := lock.Client.Expire(lock.Context, lock.key, time.Duration(lock.lockTimeout.Seconds()/3*2)*time.Second).Err(); err != nil {
+	if lock.UniversalClient.Get(lock.Context, lock.key).Val() == lock.token {
+		if err := lock.UniversalClient.Expire(lock.Context, lock.key, time.Duration(lock.lockTimeout.Seconds()/3*2)*time.Second).Err(); err != nil {
 			return fmt.Errorf("failed to renew lock: %s", err)
 		}
 	} else {
@@ -3,6 +3,8 @@ package redis_locker
 import (
 	"context"
 	"fmt"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
 	"github.com/henryxu/tools/common"
 	"testing"
 	"time"
@@ -20,3 +22,42 @@ func TestNewRedisLockUnlock(t *testing.T) {
 	err := locker.UnLock()
 	fmt.Println(err)
 }
+
+func TestRedisLockFencing(t *testing.T) {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+
+	first := NewRedisLocker(context.Background(), client, "test_fence", WithTimeout(time.Second), WithFencing())
+	if err := first.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	second := NewRedisLocker(context.Background(), client, "test_fence", WithTimeout(time.Second), WithFencing())
+	if err := second.Lock(); err == nil || second.FencingToken() != 0 {
+		t.Fatalf("expected the lock taken without a token, got %v and %d", err, second.FencingToken())
+	}
+
+	// The first holder stalls past its lock, the second takes over.
+	s.FastForward(2 * time.Second)
+	if err := second.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	if first.FencingToken() >= second.FencingToken() {
+		t.Errorf("expected increasing tokens, got %d then %d", first.FencingToken(), second.FencingToken())
+	}
+	if v, _ := s.Get(FenceKey("test_fence")); v != "2" {
+		t.Errorf("expected the counter at 2, got %q", v)
+	}
+}
+
+func TestFenceKey(t *testing.T) {
+	for key, expected := range map[string]string{
+		"exec_job":       "{exec_job}:fence",
+		"exec_{job}":     "exec_{job}:fence",
+		"exec_{}_job":    "{exec_{}_job}:fence",
+		"exec_{job}_now": "exec_{job}_now:fence",
+	} {
+		if actual := FenceKey(key); actual != expected {
+			t.Errorf("%s: expected %s, got %s", key, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package scron
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// EntryView is the JSON form of an entry served by the admin API.
+type EntryView struct {
+	ID          EntryID     `json:"id"`
+	Name        string      `json:"name"`
+	Spec        string      `json:"spec,omitempty"`
+	Description string      `json:"description,omitempty"`
+	After       []string    `json:"after,omitempty"`
+	Next        time.Time   `json:"next"`
+	Upcoming    []time.Time `json:"upcoming,omitempty"`
+	Prev        time.Time   `json:"prev"`
+	Status      string      `json:"status"`
+	Runs        int         `json:"runs"`
+	Failures    int         `json:"failures"`
+	LastError   string      `json:"last_error,omitempty"`
+	Lock        *LockView   `json:"lock,omitempty"`
+}
+
+// LockView shows who holds the lock of an entry.
+type LockView struct {
+	Key   string `json:"key"`
+	Owner string `json:"owner,omitempty"`
+}
+
+// statusNames maps entry statuses to their name in the admin API.
+var statusNames = map[int]string{
+	StatusReady:   "ready",
+	StatusRunning: "running",
+	StatusStopped: "paused",
+	StatusReset:   "reset",
+	StatusClosed:  "closed",
+}
+
+// defaultUpcoming is how many upcoming activations are listed by default, and
+// maxUpcoming how many at most.
+const (
+	defaultUpcoming = 5
+	maxUpcoming     = 100
+)
+
+// newEntryView converts an entry snapshot, describing its schedule in lang and
+// listing up to next upcoming activations, and asking the lock provider for the
+// current owner of its lock if it can tell.
+func newEntryView(e Entry, lang Language, next int) EntryView {
+	view := EntryView{
+		ID:          e.ID,
+		Name:        e.Name,
+		Spec:        e.Spec,
+		Description: Describe(e.Schedule, lang),
+		After:       e.After,
+		Next:        e.Next,
+		Upcoming:    e.Upcoming(next),
+		Prev:        e.Prev,
+		Status:      statusNames[e.status],
+		Runs:        e.Runs,
+		Failures:    e.Failures,
+	}
+	if e.LastResult.Err != nil {
+		view.LastError = e.LastResult.Err.Error()
+	}
+	if owner, ok := e.lockProvider.(LockOwner); ok {
+		view.Lock = &LockView{Key: e.GetTaskExecKey()}
+		view.Lock.Owner, _ = owner.Owner(view.Lock.Key)
+	}
+	return view
+}
+
+// AdminHandler returns an http.Handler exposing c as a JSON API:
+//
+//	GET  /entries                  list entries
+//	GET  /entry?name=N             one entry, with the owner of its lock
+//	GET  /history?name=N&limit=L   the last runs of an entry (see WithHistory)
+//	POST /pause?name=N             Pause
+//	POST /resume?name=N            Resume
+//	POST /trigger?name=N           Trigger
+//
+// Entries are described in the language given by lang, e.g. lang=zh, with up
+// to next upcoming activations, 5 by default and 100 at most.
+//
+// Mount it under a prefix of an existing mux, e.g.
+//
+//	mux.Handle("/cron/", http.StripPrefix("/cron", scron.AdminHandler(c)))
+func AdminHandler(c *Cron) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/entries", func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+		lang, next, err := viewOptions(r)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		entries := c.Entries()
+		views := make([]EntryView, 0, len(entries))
+		for _, e := range entries {
+			views = append(views, newEntryView(e, lang, next))
+		}
+		writeJSON(w, http.StatusOK, views)
+	})
+	mux.HandleFunc("/entry", func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+		name := r.URL.Query().Get("name")
+		lang, next, err := viewOptions(r)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		for _, e := range c.Entries() {
+			if e.Name == name {
+				writeJSON(w, http.StatusOK, newEntryView(e, lang, next))
+				return
+			}
+		}
+		writeError(w, ErrNotFound)
+	})
+	mux.HandleFunc("/history", func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		records, err := c.History(r.URL.Query().Get("name"), limit)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, records)
+	})
+	control := func(f func(name string) error) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if !allowMethod(w, r, http.MethodPost) {
+				return
+			}
+			if err := f(r.URL.Query().Get("name")); err != nil {
+				writeError(w, err)
+				return
+			}
+			writeJSON(w, http.StatusOK, map[string]string{"result": "ok"})
+		}
+	}
+	mux.HandleFunc("/pause", control(c.Pause))
+	mux.HandleFunc("/resume", control(c.Resume))
+	mux.HandleFunc("/trigger", control(c.Trigger))
+	return mux
+}
+
+// viewOptions returns the lang and next query parameters of r, next being
+// capped at maxUpcoming.
+func viewOptions(r *http.Request) (Language, int, error) {
+	lang := ParseLanguage(r.URL.Query().Get("lang"))
+	param := r.URL.Query().Get("next")
+	if param == "" {
+		return lang, defaultUpcoming, nil
+	}
+	next, err := strconv.Atoi(param)
+	if err != nil || next < 0 {
+		return lang, 0, fmt.Errorf("invalid next %q", param)
+	}
+	if next > maxUpcoming {
+		next = maxUpcoming
+	}
+	return lang, next, nil
+}
+
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method {
+		return true
+	}
+	w.Header().Set("Allow", method)
+	writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	return false
+}
+
+// writeError maps the errors of this package to HTTP statuses.
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch err {
+	case ErrNotFound:
+		status = http.StatusNotFound
+	case ErrLocked:
+		status = http.StatusConflict
+	case ErrNotRunning, ErrNoHistory:
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
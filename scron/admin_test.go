@@ -0,0 +1,101 @@
+package scron
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func adminRequest(t *testing.T, h http.Handler, method, target string, v interface{}) int {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+	if v != nil {
+		if err := json.NewDecoder(rec.Body).Decode(v); err != nil {
+			t.Fatalf("%s %s: %v", method, target, err)
+		}
+	}
+	return rec.Code
+}
+
+func TestAdminHandler(t *testing.T) {
+	runs := make(chan struct{}, 10)
+	cron := New(WithParser(secondParser), WithChain(), WithLogger(DiscardLogger),
+		WithLockProvider(LocalLockProvider()), WithHistory(MemoryHistory(10)))
+	cron.AddSingleton("@yearly", func() { runs <- struct{}{} }, "admin")
+	cron.Start()
+	defer cron.Stop()
+	h := AdminHandler(cron)
+
+	var entries []EntryView
+	if code := adminRequest(t, h, http.MethodGet, "/entries", &entries); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	if len(entries) != 1 || entries[0].Name != "admin" || entries[0].Spec != "@yearly" ||
+		entries[0].Status != "ready" || entries[0].Next.IsZero() {
+		t.Errorf("unexpected entries %+v", entries)
+	}
+
+	if code := adminRequest(t, h, http.MethodGet, "/pause?name=admin", nil); code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", code)
+	}
+	if code := adminRequest(t, h, http.MethodPost, "/pause?name=missing", nil); code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", code)
+	}
+	if code := adminRequest(t, h, http.MethodPost, "/pause?name=admin", nil); code != http.StatusOK {
+		t.Errorf("expected 200, got %d", code)
+	}
+
+	var entry EntryView
+	adminRequest(t, h, http.MethodGet, "/entry?name=admin", &entry)
+	if entry.Status != "paused" || entry.Lock == nil || entry.Lock.Key != "exec_admin" {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+
+	// Hold the run so that the lock owner shows up.
+	release := make(chan struct{})
+	cron.Schedule(Every(time.Hour), FuncJob(func() { runs <- struct{}{}; <-release }), "held")
+	if code := adminRequest(t, h, http.MethodPost, "/trigger?name=held", nil); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	<-runs
+	adminRequest(t, h, http.MethodGet, "/entry?name=held", &entry)
+	if entry.Status != "running" || entry.Lock == nil || entry.Lock.Owner == "" {
+		t.Errorf("expected running entry with a lock owner, got %+v", entry)
+	}
+	close(release)
+
+	if !eventually(func() bool {
+		var records []Record
+		adminRequest(t, h, http.MethodGet, "/history?name=held&limit=5", &records)
+		return len(records) == 1 && records[0].Outcome == OutcomeSuccess
+	}) {
+		t.Error("expected the triggered run in the history")
+	}
+}
+
+func TestAdminHandlerUpcomingLimit(t *testing.T) {
+	cron := New(WithParser(secondParser), WithLogger(DiscardLogger), WithLockProvider(NoopLockProvider()))
+	cron.Schedule(FixedRate(time.Hour, time.Now()), FuncJob(func() {}), "fast")
+	cron.Start()
+	defer cron.Stop()
+	h := AdminHandler(cron)
+
+	var entries []EntryView
+	if code := adminRequest(t, h, http.MethodGet, "/entries?next=100000000", &entries); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	if len(entries) != 1 || len(entries[0].Upcoming) != maxUpcoming {
+		t.Errorf("expected %d upcoming activations, got %+v", maxUpcoming, entries)
+	}
+
+	for _, next := range []string{"-1", "x"} {
+		if code := adminRequest(t, h, http.MethodGet, "/entries?next="+next, nil); code != http.StatusBadRequest {
+			t.Errorf("next=%s: expected 400, got %d", next, code)
+		}
+		if code := adminRequest(t, h, http.MethodGet, "/entry?name=fast&next="+next, nil); code != http.StatusBadRequest {
+			t.Errorf("next=%s: expected 400, got %d", next, code)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package scron
 
 import (
+	"context"
 	"fmt"
 	"runtime"
 	"sync"
@@ -22,6 +23,8 @@ func NewChain(c ...JobWrapper) Chain {
 }
 
 // Then decorates the given job with all JobWrappers in the chain.
+// The wrappers provided by this package pass the per-run context on to
+// the wrapped job.
 //
 // This:
 //
@@ -38,22 +41,24 @@ func (c Chain) Then(j Job) Job {
 }
 
 // Recover panics in wrapped jobs and log them with the provided logger.
+// The panic is reported as the run's error.
 func Recover(logger Logger) JobWrapper {
 	return func(j Job) Job {
-		return FuncJob(func() {
+		return contextFuncJob(func(ctx context.Context) (err error) {
 			defer func() {
 				if r := recover(); r != nil {
 					const size = 64 << 10
 					buf := make([]byte, size)
 					buf = buf[:runtime.Stack(buf, false)]
-					err, ok := r.(error)
+					var ok bool
+					err, ok = r.(error)
 					if !ok {
 						err = fmt.Errorf("%v", r)
 					}
 					logger.Error(err, "panic", "stack", "...\n"+string(buf))
 				}
 			}()
-			j.Run()
+			return runJob(ctx, j)
 		})
 	}
 }
@@ -64,14 +69,14 @@ func Recover(logger Logger) JobWrapper {
 func DelayIfStillRunning(logger Logger) JobWrapper {
 	return func(j Job) Job {
 		var mu sync.Mutex
-		return FuncJob(func() {
+		return contextFuncJob(func(ctx context.Context) error {
 			start := time.Now()
 			mu.Lock()
 			defer mu.Unlock()
 			if dur := time.Since(start); dur > time.Minute {
 				logger.Info("delay", "duration", dur)
 			}
-			j.Run()
+			return runJob(ctx, j)
 		})
 	}
 }
@@ -82,13 +87,14 @@ func SkipIfStillRunning(logger Logger) JobWrapper {
 	return func(j Job) Job {
 		var ch = make(chan struct{}, 1)
 		ch <- struct{}{}
-		return FuncJob(func() {
+		return contextFuncJob(func(ctx context.Context) error {
 			select {
 			case v := <-ch:
 				defer func() { ch <- v }()
-				j.Run()
+				return runJob(ctx, j)
 			default:
 				logger.Info("skip")
+				return nil
 			}
 		})
 	}
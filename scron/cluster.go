@@ -0,0 +1,182 @@
+package scron
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/henryxu/tools/common"
+)
+
+// Operations carried by a Command.
+const (
+	CommandPause   = "pause"
+	CommandResume  = "resume"
+	CommandTrigger = "trigger"
+	CommandRemove  = "remove"
+)
+
+// Command is an operation on a named entry, broadcast to every Cron in the
+// cluster through a ControlChannel.
+type Command struct {
+	Op   string `json:"op"`
+	Name string `json:"name"`
+
+	// At is the activation time of a trigger. Every node locks the same
+	// activation, so only one of them runs it. Broadcast fills it in.
+	At time.Time `json:"at,omitempty"`
+}
+
+// ControlChannel carries Commands between the Crons of a cluster.
+type ControlChannel interface {
+	// Publish sends cmd to every subscribed Cron, including the sender.
+	Publish(ctx context.Context, cmd Command) error
+
+	// Subscribe returns the commands published from now on. The channel is
+	// closed once ctx is done.
+	Subscribe(ctx context.Context) (<-chan Command, error)
+
+	// Paused returns the names of the entries paused cluster-wide, so that
+	// Crons started later pause them too.
+	Paused(ctx context.Context) ([]string, error)
+}
+
+// defaultControlChannel is the Redis channel used when none is given.
+const defaultControlChannel = "scron_control"
+
+// redisControlChannel broadcasts commands through Redis pub/sub and keeps the
+// paused entries in a Redis set next to it.
+type redisControlChannel struct {
+	client  redis.UniversalClient
+	channel string
+}
+
+// RedisControlChannel returns a ControlChannel over the given Redis pub/sub
+// channel. A nil client falls back to common.NewRedisClient and an empty
+// channel to "scron_control".
+func RedisControlChannel(client redis.UniversalClient, channel string) ControlChannel {
+	if channel == "" {
+		channel = defaultControlChannel
+	}
+	return &redisControlChannel{client: client, channel: channel}
+}
+
+func (ch *redisControlChannel) redis() redis.UniversalClient {
+	if ch.client == nil {
+		return common.NewRedisClient()
+	}
+	return ch.client
+}
+
+// 获取key 暂停的任务集合
+func (ch *redisControlChannel) pausedKey() string {
+	return fmt.Sprintf("%s_paused", ch.channel)
+}
+
+func (ch *redisControlChannel) Publish(ctx context.Context, cmd Command) error {
+	msg, err := json.Marshal(cmd)
+	if err != nil {
+		return err
+	}
+	_, err = ch.redis().TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		switch cmd.Op {
+		case CommandPause:
+			pipe.SAdd(ctx, ch.pausedKey(), cmd.Name)
+		case CommandResume, CommandRemove:
+			pipe.SRem(ctx, ch.pausedKey(), cmd.Name)
+		}
+		pipe.Publish(ctx, ch.channel, msg)
+		return nil
+	})
+	return err
+}
+
+func (ch *redisControlChannel) Subscribe(ctx context.Context) (<-chan Command, error) {
+	ps := ch.redis().Subscribe(ctx, ch.channel)
+	// 等待订阅成功，之后发布的命令不会丢失
+	if _, err := ps.Receive(ctx); err != nil {
+		ps.Close()
+		return nil, err
+	}
+	commands := make(chan Command)
+	go func() {
+		defer close(commands)
+		defer ps.Close()
+		msgs := ps.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-msgs:
+				if !ok {
+					return
+				}
+				var cmd Command
+				if err := json.Unmarshal([]byte(msg.Payload), &cmd); err != nil {
+					continue
+				}
+				select {
+				case commands <- cmd:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return commands, nil
+}
+
+func (ch *redisControlChannel) Paused(ctx context.Context) ([]string, error) {
+	return ch.redis().SMembers(ctx, ch.pausedKey()).Result()
+}
+
+// Broadcast sends cmd to every Cron in the cluster, this one included,
+// through the ControlChannel given by WithControlChannel.
+func (c *Cron) Broadcast(cmd Command) error {
+	if c.controlChannel == nil {
+		return ErrNoControlChannel
+	}
+	if cmd.Op == CommandTrigger && cmd.At.IsZero() {
+		cmd.At = c.now().Truncate(time.Second)
+	}
+	return c.controlChannel.Publish(context.Background(), cmd)
+}
+
+// listen applies the commands received on the ControlChannel until ctx is
+// done. It starts by pausing the entries paused cluster-wide; entries added
+// later under one of those names are paused too.
+func (c *Cron) listen(ctx context.Context) {
+	commands, err := c.controlChannel.Subscribe(ctx)
+	if err != nil {
+		c.logger.Error(err, "control subscribe")
+		return
+	}
+	paused, err := c.controlChannel.Paused(ctx)
+	if err != nil {
+		c.logger.Error(err, "control paused")
+	}
+	for _, name := range paused {
+		c.sendControl(controlRequest{op: controlPause, name: name, cluster: true})
+	}
+	for cmd := range commands {
+		req := controlRequest{name: cmd.Name, at: cmd.At, cluster: true}
+		switch cmd.Op {
+		case CommandPause:
+			req.op = controlPause
+		case CommandResume:
+			req.op = controlResume
+		case CommandTrigger:
+			req.op = controlTrigger
+		case CommandRemove:
+			req.op = controlRemove
+		default:
+			logWarn(c.logger, "control", "unknown", cmd.Op)
+			continue
+		}
+		if err := c.sendControl(req); err != nil && err != ErrNotFound {
+			c.logger.Error(err, "control", "op", cmd.Op, "entry", cmd.Name)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package scron
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+// newCluster returns n Crons sharing a control channel and a lock, as if
+// they ran on different nodes.
+func newCluster(t *testing.T, n int, runs *int32) []*Cron {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	locks := LocalLockProvider()
+
+	var nodes []*Cron
+	for i := 0; i < n; i++ {
+		cron := New(WithParser(secondParser), WithChain(), WithLogger(DiscardLogger),
+			WithLockProvider(locks), WithControlChannel(RedisControlChannel(client, "")))
+		cron.AddSingleton("@yearly", func() { atomic.AddInt32(runs, 1) }, "cluster")
+		nodes = append(nodes, cron)
+	}
+	return nodes
+}
+
+// eventually polls cond for up to a second.
+func eventually(cond func() bool) bool {
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); {
+		if cond() {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
+
+func TestBroadcastPauseResume(t *testing.T) {
+	var runs int32
+	nodes := newCluster(t, 2, &runs)
+	nodes[0].Start()
+	defer nodes[0].Stop()
+	time.Sleep(50 * time.Millisecond)
+
+	if err := nodes[0].Broadcast(Command{Op: CommandPause, Name: "cluster"}); err != nil {
+		t.Fatal(err)
+	}
+	if !eventually(func() bool { return nodes[0].Entries()[0].Status() == StatusStopped }) {
+		t.Fatal("expected entry to be paused")
+	}
+
+	// A node started later picks up the pause.
+	nodes[1].Start()
+	defer nodes[1].Stop()
+	if !eventually(func() bool { return nodes[1].Entries()[0].Status() == StatusStopped }) {
+		t.Error("expected late node to pause the entry")
+	}
+
+	nodes[1].Broadcast(Command{Op: CommandResume, Name: "cluster"})
+	for i, node := range nodes {
+		if !eventually(func() bool { return node.Entries()[0].Status() == StatusReady }) {
+			t.Errorf("expected node %d to resume the entry", i)
+		}
+	}
+}
+
+func TestBroadcastPauseAppliesToEntriesAddedLater(t *testing.T) {
+	var runs int32
+	nodes := newCluster(t, 2, &runs)
+	nodes[0].Start()
+	defer nodes[0].Stop()
+	time.Sleep(50 * time.Millisecond)
+
+	if err := nodes[0].Broadcast(Command{Op: CommandPause, Name: "later"}); err != nil {
+		t.Fatal(err)
+	}
+	nodes[1].Start()
+	defer nodes[1].Stop()
+	time.Sleep(50 * time.Millisecond)
+
+	status := func(node *Cron) int {
+		for _, e := range node.Entries() {
+			if e.Name == "later" {
+				return e.Status()
+			}
+		}
+		return -1
+	}
+	for i, node := range nodes {
+		if _, err := node.AddSingleton("@every 1s", func() {}, "later"); err != nil {
+			t.Fatal(err)
+		}
+		if !eventually(func() bool { return status(node) == StatusStopped }) {
+			t.Errorf("expected node %d to pause the entry added after the pause", i)
+		}
+	}
+
+	nodes[0].Broadcast(Command{Op: CommandResume, Name: "later"})
+	for i, node := range nodes {
+		if !eventually(func() bool { return status(node) == StatusReady }) {
+			t.Errorf("expected node %d to resume the entry", i)
+		}
+	}
+}
+
+func TestBroadcastTriggerRunsOnce(t *testing.T) {
+	var runs int32
+	nodes := newCluster(t, 3, &runs)
+	for _, node := range nodes {
+		node.Start()
+		defer node.Stop()
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	if err := nodes[0].Broadcast(Command{Op: CommandTrigger, Name: "cluster"}); err != nil {
+		t.Fatal(err)
+	}
+	if !eventually(func() bool { return atomic.LoadInt32(&runs) > 0 }) {
+		t.Fatal("expected triggered entry to run")
+	}
+	time.Sleep(100 * time.Millisecond)
+	if n := atomic.LoadInt32(&runs); n != 1 {
+		t.Errorf("expected a single run across the cluster, got %d", n)
+	}
+}
+
+func TestBroadcastRemove(t *testing.T) {
+	var runs int32
+	nodes := newCluster(t, 2, &runs)
+	for _, node := range nodes {
+		node.Start()
+		defer node.Stop()
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	nodes[1].Broadcast(Command{Op: CommandRemove, Name: "cluster"})
+	for i, node := range nodes {
+		if !eventually(func() bool { return len(node.Entries()) == 0 }) {
+			t.Errorf("expected node %d to remove the entry", i)
+		}
+	}
+	if err := New().Broadcast(Command{Op: CommandPause}); err != ErrNoControlChannel {
+		t.Errorf("expected %v, got %v", ErrNoControlChannel, err)
+	}
+}
@@ -0,0 +1,350 @@
+package scron
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Concurrency policies of a JobConfig, for runs overlapping on one node.
+const (
+	ConcurrencyAllow = "allow" // default, runs may overlap
+	ConcurrencySkip  = "skip"  // see SkipIfStillRunning
+	ConcurrencyDelay = "delay" // see DelayIfStillRunning
+)
+
+// Config is the content of a job configuration file, e.g.
+//
+//	jobs:
+//	  - name: report
+//	    spec: "0 0 2 * * *"
+//	    timezone: Asia/Shanghai
+//	    timeout: 10m
+//	    retry: {max_attempts: 3, backoff: 1s, max_backoff: 1m}
+//	    concurrency: skip
+//	  - name: cleanup
+//	    after: [report]
+//	    enabled: false
+type Config struct {
+	Jobs []JobConfig `json:"jobs" yaml:"jobs"`
+}
+
+// JobConfig configures one entry. Exactly one of Spec and After is set.
+type JobConfig struct {
+	// Name is the name of the entry.
+	Name string `json:"name" yaml:"name"`
+	// Func is the name the job was registered with, Name if empty.
+	Func string `json:"func,omitempty" yaml:"func,omitempty"`
+	// Spec is the schedule, parsed by the Cron's parser.
+	Spec string `json:"spec,omitempty" yaml:"spec,omitempty"`
+	// After lists the upstream entries, see AddAfter.
+	After []string `json:"after,omitempty" yaml:"after,omitempty"`
+	// Timezone is the IANA time zone of Spec, the Cron's location if empty.
+	Timezone string `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+	// Enabled false adds the entry paused. Default true.
+	Enabled *bool `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	// Timeout is passed to WithJobTimeout.
+	Timeout Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	// Retry is passed to WithRetry.
+	Retry *RetryConfig `json:"retry,omitempty" yaml:"retry,omitempty"`
+	// Concurrency is one of the Concurrency* policies.
+	Concurrency string `json:"concurrency,omitempty" yaml:"concurrency,omitempty"`
+	// Jitter is passed to WithJitter.
+	Jitter Duration `json:"jitter,omitempty" yaml:"jitter,omitempty"`
+}
+
+// RetryConfig is the configurable part of a RetryPolicy.
+type RetryConfig struct {
+	MaxAttempts int      `json:"max_attempts" yaml:"max_attempts"`
+	Backoff     Duration `json:"backoff,omitempty" yaml:"backoff,omitempty"`
+	MaxBackoff  Duration `json:"max_backoff,omitempty" yaml:"max_backoff,omitempty"`
+	Jitter      float64  `json:"jitter,omitempty" yaml:"jitter,omitempty"`
+}
+
+// Duration is a time.Duration written as in time.ParseDuration, e.g. "1m30s".
+type Duration time.Duration
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// ParseConfig decodes a configuration in JSON or YAML, according to the
+// extension of path. Unknown fields are rejected.
+func ParseConfig(path string, data []byte) (*Config, error) {
+	var config Config
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&config); err != nil {
+			return nil, fmt.Errorf("scron: config %s: %w", path, err)
+		}
+		return &config, nil
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("scron: config %s: %w", path, err)
+	}
+	return &config, nil
+}
+
+// Loader adds the entries configured in a file to a Cron, binding them to
+// jobs registered by name, and applies the changes to the file while the
+// Cron is running.
+//
+//	loader := scron.NewLoader(cron, "/etc/app/cron.yaml")
+//	loader.RegisterFunc("report", Report)
+//	if err := loader.Load(); err != nil {
+//		...
+//	}
+//	go loader.Watch(ctx, 10*time.Second)
+type Loader struct {
+	cron *Cron
+	path string
+
+	mu     sync.Mutex
+	jobs   map[string]Job
+	loaded map[string]JobConfig
+	data   []byte
+}
+
+// NewLoader returns a Loader for the given file.
+func NewLoader(c *Cron, path string) *Loader {
+	return &Loader{
+		cron:   c,
+		path:   path,
+		jobs:   make(map[string]Job),
+		loaded: make(map[string]JobConfig),
+	}
+}
+
+// Register binds a job to a name used in the Func or Name of a JobConfig.
+func (l *Loader) Register(name string, job Job) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.jobs[name] = job
+}
+
+// RegisterFunc is Register for a func().
+func (l *Loader) RegisterFunc(name string, cmd func()) {
+	l.Register(name, FuncJob(cmd))
+}
+
+// RegisterContext is Register for a ContextJob.
+func (l *Loader) RegisterContext(name string, job ContextJob) {
+	l.Register(name, contextFuncJob(job.Run))
+}
+
+// Load reads the file and brings the Cron in line with it: new jobs are
+// added, rescheduled or replaced when changed, and those no longer in the
+// file removed.
+// Entries added in code are left alone. Nothing is applied if the file is
+// invalid.
+func (l *Loader) Load() error {
+	data, err := os.ReadFile(l.path)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.apply(data)
+}
+
+// Watch reloads the file every interval when its content changed, until ctx
+// is done. Errors are logged and the previous configuration stays in effect.
+func (l *Loader) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		data, err := os.ReadFile(l.path)
+		if err != nil {
+			l.cron.logger.Error(err, "config", "path", l.path)
+			continue
+		}
+		l.mu.Lock()
+		if !bytes.Equal(data, l.data) {
+			if err := l.apply(data); err != nil {
+				l.cron.logger.Error(err, "config", "path", l.path)
+			}
+		}
+		l.mu.Unlock()
+	}
+}
+
+func (l *Loader) apply(data []byte) error {
+	config, err := ParseConfig(l.path, data)
+	if err != nil {
+		return err
+	}
+	wanted := make(map[string]JobConfig, len(config.Jobs))
+	for _, job := range config.Jobs {
+		if _, ok := wanted[job.Name]; ok {
+			return fmt.Errorf("scron: config %s: duplicate job %q", l.path, job.Name)
+		}
+		if err := l.validate(job); err != nil {
+			return fmt.Errorf("scron: config %s: job %q: %w", l.path, job.Name, err)
+		}
+		wanted[job.Name] = job
+	}
+
+	var errs []error
+	for name, old := range l.loaded {
+		job, ok := wanted[name]
+		if ok && old.rescheduled(job) {
+			spec, _ := job.spec()
+			if err := l.cron.Reschedule(name, spec); err == nil {
+				l.loaded[name] = job
+				l.cron.logger.Info("config", "rescheduled", name)
+				continue
+			}
+		}
+		if !ok || !reflect.DeepEqual(old, job) {
+			// An entry already removed in code is as good as removed here.
+			err := l.cron.sendControl(controlRequest{op: controlRemove, name: name})
+			if err != nil && err != ErrNotFound {
+				errs = append(errs, fmt.Errorf("scron: config %s: job %q: %w", l.path, name, err))
+				continue
+			}
+			delete(l.loaded, name)
+			l.cron.logger.Info("config", "removed", name)
+		}
+	}
+	for _, job := range config.Jobs {
+		if _, ok := l.loaded[job.Name]; ok {
+			continue
+		}
+		if err := l.add(job); err != nil {
+			errs = append(errs, fmt.Errorf("scron: config %s: job %q: %w", l.path, job.Name, err))
+			continue
+		}
+		l.loaded[job.Name] = job
+		l.cron.logger.Info("config", "added", job.Name)
+	}
+	if len(errs) > 0 {
+		// Applied again by Watch on its next tick, even if the file is unchanged.
+		return errors.Join(errs...)
+	}
+	l.data = data
+	return nil
+}
+
+// validate checks what can be checked before touching the Cron.
+func (l *Loader) validate(job JobConfig) error {
+	if job.Name == "" {
+		return errors.New("missing name")
+	}
+	if _, ok := l.jobs[job.funcName()]; !ok {
+		return fmt.Errorf("no job registered as %q", job.funcName())
+	}
+	if (job.Spec == "") == (len(job.After) == 0) {
+		return errors.New("exactly one of spec and after must be set")
+	}
+	if job.Spec != "" {
+		spec, err := job.spec()
+		if err != nil {
+			return err
+		}
+		if _, err := l.cron.parser.Parse(spec); err != nil {
+			return err
+		}
+	}
+	switch job.Concurrency {
+	case "", ConcurrencyAllow, ConcurrencySkip, ConcurrencyDelay:
+	default:
+		return fmt.Errorf("unknown concurrency %q", job.Concurrency)
+	}
+	return nil
+}
+
+func (l *Loader) add(job JobConfig) error {
+	var opts []EntryOption
+	if job.Enabled != nil && !*job.Enabled {
+		opts = append(opts, withStatus(StatusStopped))
+	}
+	if job.Timeout > 0 {
+		opts = append(opts, WithJobTimeout(time.Duration(job.Timeout)))
+	}
+	if job.Jitter > 0 {
+		opts = append(opts, WithJitter(time.Duration(job.Jitter)))
+	}
+	if job.Retry != nil {
+		opts = append(opts, WithRetry(RetryPolicy{
+			MaxAttempts: job.Retry.MaxAttempts,
+			Backoff:     time.Duration(job.Retry.Backoff),
+			MaxBackoff:  time.Duration(job.Retry.MaxBackoff),
+			Jitter:      job.Retry.Jitter,
+		}))
+	}
+	switch job.Concurrency {
+	case ConcurrencySkip:
+		opts = append(opts, WithWrappers(SkipIfStillRunning(l.cron.logger)))
+	case ConcurrencyDelay:
+		opts = append(opts, WithWrappers(DelayIfStillRunning(l.cron.logger)))
+	}
+
+	cmd := l.jobs[job.funcName()]
+	var err error
+	if job.Spec != "" {
+		spec, _ := job.spec()
+		_, err = l.cron.AddJob(spec, cmd, job.Name, opts...)
+	} else {
+		_, err = l.cron.AddAfter(job.After, cmd, job.Name, opts...)
+	}
+	return err
+}
+
+func (job JobConfig) funcName() string {
+	if job.Func != "" {
+		return job.Func
+	}
+	return job.Name
+}
+
+// rescheduled reports whether only the schedule differs in next, so that the
+// entry can be rescheduled in place.
+func (job JobConfig) rescheduled(next JobConfig) bool {
+	if job.Spec == "" || next.Spec == "" || reflect.DeepEqual(job, next) {
+		return false
+	}
+	job.Spec, job.Timezone = next.Spec, next.Timezone
+	return reflect.DeepEqual(job, next)
+}
+
+// spec returns the Spec with the Timezone as a CRON_TZ prefix.
+func (job JobConfig) spec() (string, error) {
+	if job.Timezone == "" {
+		return job.Spec, nil
+	}
+	if strings.HasPrefix(job.Spec, "TZ=") || strings.HasPrefix(job.Spec, "CRON_TZ=") {
+		return "", errors.New("both timezone and a TZ prefix in spec")
+	}
+	if _, err := time.LoadLocation(job.Timezone); err != nil {
+		return "", err
+	}
+	return "CRON_TZ=" + job.Timezone + " " + job.Spec, nil
+}
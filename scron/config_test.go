@@ -0,0 +1,212 @@
+package scron
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseConfig(t *testing.T) {
+	yamlConfig := `
+jobs:
+  - name: report
+    spec: "0 0 2 * * *"
+    timezone: Asia/Shanghai
+    timeout: 10m
+    retry: {max_attempts: 3, backoff: 1s}
+    concurrency: skip
+  - name: cleanup
+    func: clean
+    after: [report]
+    enabled: false
+`
+	jsonConfig := `{"jobs": [
+  {"name": "report", "spec": "0 0 2 * * *", "timezone": "Asia/Shanghai", "timeout": "10m",
+   "retry": {"max_attempts": 3, "backoff": "1s"}, "concurrency": "skip"},
+  {"name": "cleanup", "func": "clean", "after": ["report"], "enabled": false}
+]}`
+	for path, data := range map[string]string{"cron.yaml": yamlConfig, "cron.json": jsonConfig} {
+		config, err := ParseConfig(path, []byte(data))
+		if err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		if len(config.Jobs) != 2 {
+			t.Fatalf("%s: expected 2 jobs, got %+v", path, config.Jobs)
+		}
+		report, cleanup := config.Jobs[0], config.Jobs[1]
+		if report.Timezone != "Asia/Shanghai" || time.Duration(report.Timeout) != 10*time.Minute ||
+			report.Retry == nil || report.Retry.MaxAttempts != 3 || time.Duration(report.Retry.Backoff) != time.Second ||
+			report.Concurrency != ConcurrencySkip {
+			t.Errorf("%s: unexpected report %+v", path, report)
+		}
+		if cleanup.funcName() != "clean" || len(cleanup.After) != 1 || cleanup.Enabled == nil || *cleanup.Enabled {
+			t.Errorf("%s: unexpected cleanup %+v", path, cleanup)
+		}
+	}
+
+	if _, err := ParseConfig("cron.yaml", []byte("jobs:\n  - name: a\n    shedule: x\n")); err == nil {
+		t.Error("expected unknown fields to be rejected")
+	}
+}
+
+func writeConfig(t *testing.T, path, data string) {
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func entrySpecs(c *Cron) map[string]string {
+	specs := make(map[string]string)
+	for _, e := range c.Entries() {
+		specs[e.Name] = e.Spec
+		if e.status == StatusStopped {
+			specs[e.Name] += " (paused)"
+		}
+	}
+	return specs
+}
+
+func TestLoaderLoadAndReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cron.yaml")
+	writeConfig(t, path, `
+jobs:
+  - name: a
+    spec: "@hourly"
+  - name: b
+    spec: "@daily"
+    enabled: false
+`)
+	cron := New(WithParser(secondParser), WithLogger(DiscardLogger), WithLockProvider(NoopLockProvider()))
+	cron.AddSingleton("@yearly", func() {}, "code")
+	loader := NewLoader(cron, path)
+	loader.RegisterFunc("a", func() {})
+	loader.RegisterFunc("b", func() {})
+	if err := loader.Load(); err != nil {
+		t.Fatal(err)
+	}
+	cron.Start()
+	defer cron.Stop()
+
+	got := entrySpecs(cron)
+	if len(got) != 3 || got["a"] != "@hourly" || got["b"] != "@daily (paused)" || got["code"] != "@yearly" {
+		t.Fatalf("unexpected entries %v", got)
+	}
+
+	// An invalid file changes nothing.
+	writeConfig(t, path, "jobs:\n  - name: a\n    spec: \"@hourly\"\n  - name: c\n    spec: \"@daily\"\n")
+	if err := loader.Load(); err == nil || !strings.Contains(err.Error(), `no job registered as "c"`) {
+		t.Errorf("expected a missing job error, got %v", err)
+	}
+	if got := entrySpecs(cron); len(got) != 3 {
+		t.Errorf("expected the entries unchanged, got %v", got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go loader.Watch(ctx, 10*time.Millisecond)
+	writeConfig(t, path, `
+jobs:
+  - name: b
+    spec: "@weekly"
+    timezone: Asia/Tokyo
+`)
+	if !eventually(func() bool {
+		got = entrySpecs(cron)
+		return len(got) == 2 && got["b"] == "CRON_TZ=Asia/Tokyo @weekly" && got["code"] == "@yearly"
+	}) {
+		t.Errorf("expected b rescheduled and a removed, got %v", got)
+	}
+}
+
+func TestLoaderConcurrencySkip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cron.json")
+	writeConfig(t, path, `{"jobs": [{"name": "slow", "spec": "@yearly", "concurrency": "skip"}]}`)
+	cron := New(WithParser(secondParser), WithChain(), WithLogger(DiscardLogger), WithLockProvider(NoopLockProvider()))
+	started, release := make(chan struct{}, 2), make(chan struct{})
+	loader := NewLoader(cron, path)
+	loader.RegisterFunc("slow", func() { started <- struct{}{}; <-release })
+	if err := loader.Load(); err != nil {
+		t.Fatal(err)
+	}
+	cron.Start()
+	defer cron.Stop()
+
+	cron.Trigger("slow")
+	<-started
+	cron.Trigger("slow")
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	if len(started) != 0 {
+		t.Error("expected the second run to be skipped")
+	}
+}
+
+func TestLoaderReschedulesInPlace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cron.yaml")
+	writeConfig(t, path, "jobs:\n  - name: a\n    spec: \"@hourly\"\n")
+	cron := New(WithParser(secondParser), WithLogger(DiscardLogger), WithLockProvider(NoopLockProvider()))
+	loader := NewLoader(cron, path)
+	loader.RegisterFunc("a", func() {})
+	if err := loader.Load(); err != nil {
+		t.Fatal(err)
+	}
+	id := cron.Entries()[0].ID
+
+	writeConfig(t, path, "jobs:\n  - name: a\n    spec: \"@daily\"\n")
+	if err := loader.Load(); err != nil {
+		t.Fatal(err)
+	}
+	entries := cron.Entries()
+	if len(entries) != 1 || entries[0].ID != id || entries[0].Spec != "@daily" {
+		t.Errorf("expected entry %d rescheduled, got %+v", id, entries)
+	}
+}
+
+func TestLoaderRetriesFailedApply(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cron.yaml")
+	writeConfig(t, path, "jobs:\n  - name: a\n    spec: \"@hourly\"\n")
+	cron := New(WithParser(secondParser), WithLogger(DiscardLogger), WithLockProvider(NoopLockProvider()))
+	cron.AddSingleton("@yearly", func() {}, "a")
+	loader := NewLoader(cron, path)
+	loader.RegisterFunc("a", func() {})
+	if err := loader.Load(); err == nil || !strings.Contains(err.Error(), ErrDuplicateName.Error()) {
+		t.Fatalf("expected a duplicate name error, got %v", err)
+	}
+	cron.Start()
+	defer cron.Stop()
+
+	// The file is unchanged, but was not applied.
+	cron.RemoveByName("a")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go loader.Watch(ctx, 10*time.Millisecond)
+	if !eventually(func() bool { return entrySpecs(cron)["a"] == "@hourly" }) {
+		t.Errorf("expected the config applied again, got %v", entrySpecs(cron))
+	}
+}
+
+func TestLoaderAddsDisabledPaused(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cron.yaml")
+	writeConfig(t, path, "jobs:\n  - name: a\n    spec: \"* * * * * ?\"\n    enabled: false\n")
+	cron := New(WithParser(secondParser), WithLogger(DiscardLogger), WithLockProvider(NoopLockProvider()))
+	runs := make(chan struct{}, 10)
+	loader := NewLoader(cron, path)
+	loader.RegisterFunc("a", func() { runs <- struct{}{} })
+	cron.Start()
+	defer cron.Stop()
+
+	if err := loader.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if got := entrySpecs(cron)["a"]; got != "* * * * * ? (paused)" {
+		t.Errorf("expected a added paused, got %q", got)
+	}
+	select {
+	case <-runs:
+		t.Error("expected a disabled entry never to run")
+	case <-time.After(OneSecond):
+	}
+}
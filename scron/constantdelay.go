@@ -25,3 +25,69 @@ func Every(duration time.Duration) ConstantDelaySchedule {
 func (schedule ConstantDelaySchedule) Next(t time.Time) time.Time {
 	return t.Add(schedule.Delay - time.Duration(t.Nanosecond())*time.Nanosecond)
 }
+
+// FixedRateSchedule activates every Interval from Start, e.g. "Every 250ms
+// from 09:00:00.100". Activations stay on that grid however late the scheduler
+// wakes, so that they do not drift.
+type FixedRateSchedule struct {
+	Start    time.Time
+	Interval time.Duration
+}
+
+// FixedRate returns a Schedule that activates at start and every interval
+// after it. Intervals are truncated to the millisecond, with a minimum of one
+// millisecond.
+func FixedRate(interval time.Duration, start time.Time) FixedRateSchedule {
+	interval -= interval % time.Millisecond
+	if interval < time.Millisecond {
+		interval = time.Millisecond
+	}
+	return FixedRateSchedule{Start: start, Interval: interval}
+}
+
+// Next returns the first activation on the grid after t.
+func (schedule FixedRateSchedule) Next(t time.Time) time.Time {
+	if t.Before(schedule.Start) {
+		return schedule.Start
+	}
+	n := t.Sub(schedule.Start)/schedule.Interval + 1
+	return schedule.Start.Add(n * schedule.Interval)
+}
+
+// FixedDelaySchedule activates Delay after the previous run completed, e.g.
+// "5s after the last run finished", so that runs never overlap on a node.
+// The Cron measures from completion; Next alone measures from t.
+type FixedDelaySchedule struct {
+	Delay time.Duration
+}
+
+// fixedDelay reports whether schedule is a FixedDelaySchedule, possibly
+// wrapped in schedules with an Unwrap method such as Jitter or Bounded.
+func fixedDelay(schedule Schedule) bool {
+	for {
+		switch s := schedule.(type) {
+		case FixedDelaySchedule:
+			return true
+		case interface{ Unwrap() Schedule }:
+			schedule = s.Unwrap()
+		default:
+			return false
+		}
+	}
+}
+
+// FixedDelay returns a Schedule that activates delay after every run
+// completes. Delays are truncated to the millisecond, with a minimum of one
+// millisecond.
+func FixedDelay(delay time.Duration) FixedDelaySchedule {
+	delay -= delay % time.Millisecond
+	if delay < time.Millisecond {
+		delay = time.Millisecond
+	}
+	return FixedDelaySchedule{Delay: delay}
+}
+
+// Next returns t plus the delay.
+func (schedule FixedDelaySchedule) Next(t time.Time) time.Time {
+	return t.Add(schedule.Delay)
+}
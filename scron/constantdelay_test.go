@@ -52,3 +52,78 @@ func TestConstantDelayNext(t *testing.T) {
 		}
 	}
 }
+
+func TestFixedRateNext(t *testing.T) {
+	start := getTime("Mon Jul 9 14:45:00 2012").Add(100 * time.Millisecond)
+	s := FixedRate(250*time.Millisecond+50*time.Nanosecond, start)
+	tests := []struct {
+		from     time.Time
+		expected time.Time
+	}{
+		{start.Add(-time.Hour), start},
+		{start, start.Add(250 * time.Millisecond)},
+		{start.Add(10 * time.Millisecond), start.Add(250 * time.Millisecond)},
+		{start.Add(249 * time.Millisecond), start.Add(250 * time.Millisecond)},
+		// A late wake does not shift the grid.
+		{start.Add(time.Second + 300*time.Millisecond), start.Add(time.Second + 500*time.Millisecond)},
+	}
+	for _, c := range tests {
+		if actual := s.Next(c.from); !actual.Equal(c.expected) {
+			t.Errorf("from %v: expected %v, got %v", c.from, c.expected, actual)
+		}
+	}
+	if FixedRate(0, start).Interval != time.Millisecond {
+		t.Error("expected the interval rounded up to a millisecond")
+	}
+}
+
+func TestFixedRateRunsBelowASecond(t *testing.T) {
+	cron := New(WithChain(), WithLogger(DiscardLogger), WithLockProvider(LocalLockProvider()))
+	runs := make(chan time.Time, 100)
+	cron.Schedule(FixedRate(100*time.Millisecond, time.Now()), FuncJob(func() { runs <- time.Now() }), "fast")
+	cron.Start()
+	time.Sleep(time.Second)
+	cron.Stop()
+
+	if n := len(runs); n < 5 {
+		t.Errorf("expected about 10 runs in a second, got %d", n)
+	}
+}
+
+func TestFixedDelayMeasuredFromCompletion(t *testing.T) {
+	for name, add := range map[string]func(c *Cron, job Job){
+		"plain": func(c *Cron, job Job) {
+			c.Schedule(FixedDelay(200*time.Millisecond), job, "slow")
+		},
+		"jitter": func(c *Cron, job Job) {
+			c.Schedule(FixedDelay(200*time.Millisecond), job, "slow", WithJitter(50*time.Millisecond))
+		},
+		"bounded": func(c *Cron, job Job) {
+			c.Schedule(Bounded(FixedDelay(200*time.Millisecond), time.Now(), time.Time{}), job, "slow")
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cron := New(WithChain(), WithLogger(DiscardLogger), WithLockProvider(LocalLockProvider()))
+			starts := make(chan time.Time, 10)
+			add(cron, FuncJob(func() {
+				starts <- time.Now()
+				time.Sleep(300 * time.Millisecond)
+			}))
+			cron.Start()
+			defer cron.Stop()
+
+			first := <-starts
+			select {
+			case second := <-starts:
+				if gap := second.Sub(first); gap < 500*time.Millisecond {
+					t.Errorf("expected the delay measured from completion, got %v between starts", gap)
+				}
+			case <-time.After(2 * OneSecond):
+				t.Fatal("expected the job to run again after the delay")
+			}
+			if len(cron.Entries()) != 1 {
+				t.Error("expected the entry kept while its run completes")
+			}
+		})
+	}
+}
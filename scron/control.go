@@ -0,0 +1,222 @@
+package scron
+
+import (
+	"errors"
+	"time"
+
+	scron "github.com/henryxu/tools/scron/cron_locker"
+)
+
+var (
+	// ErrNotFound is returned when no entry has the given name or ID.
+	ErrNotFound = errors.New("scron: entry not found")
+
+	// ErrDuplicateName is returned when adding an entry with the name of an
+	// existing one.
+	ErrDuplicateName = errors.New("scron: duplicate entry name")
+
+	// ErrNotRunning is returned by Trigger when the Cron is not started.
+	ErrNotRunning = errors.New("scron: cron is not running")
+
+	// ErrLocked is returned by Trigger when the run could not be locked,
+	// usually because the job is still running somewhere in the cluster.
+	ErrLocked = errors.New("scron: entry is locked by another run")
+
+	// ErrNoControlChannel is returned by Broadcast when no ControlChannel
+	// is configured.
+	ErrNoControlChannel = errors.New("scron: no control channel configured")
+)
+
+// controlOp is an operation on a single entry, applied by the scheduler.
+type controlOp int
+
+const (
+	controlPause controlOp = iota
+	controlResume
+	controlTrigger
+	controlRemove
+	controlDone
+	controlReschedule
+	controlUpdate
+	controlFinished
+)
+
+// controlRequest asks the scheduler goroutine to apply op to the named entry.
+// The entry is looked up by id if set, by name otherwise.
+// at is the activation time of a trigger, zero meaning now, or of a completed
+// run, which finished with err. schedule and spec replace those of the entry
+// on reschedule, job its job on update.
+// cluster marks a pause, resume or remove received on the ControlChannel,
+// whose name is remembered for entries added later.
+type controlRequest struct {
+	op       controlOp
+	id       EntryID
+	name     string
+	at       time.Time
+	err      error
+	schedule Schedule
+	spec     string
+	job      Job
+	cluster  bool
+	reply    chan error
+}
+
+// Pause stops the named entry from being run on schedule until Resume is
+// called. A run already in progress is not interrupted.
+func (c *Cron) Pause(name string) error {
+	return c.sendControl(controlRequest{op: controlPause, name: name})
+}
+
+// Resume lets a paused entry run on schedule again, from its next activation.
+func (c *Cron) Resume(name string) error {
+	return c.sendControl(controlRequest{op: controlResume, name: name})
+}
+
+// Trigger runs the named entry now, out of schedule, whether it is paused or
+// not. The run takes the entry's lock like a scheduled one.
+func (c *Cron) Trigger(name string) error {
+	return c.sendControl(controlRequest{op: controlTrigger, name: name})
+}
+
+// Reschedule replaces the schedule of the named entry with the given spec,
+// keeping its job, status, last run and history. The next activation is
+// computed from now; a run in progress is not interrupted.
+func (c *Cron) Reschedule(name, spec string) error {
+	schedule, err := c.parser.Parse(spec)
+	if err != nil {
+		return err
+	}
+	return c.sendControl(controlRequest{op: controlReschedule, name: name, schedule: schedule, spec: spec})
+}
+
+// UpdateJob replaces the job of the named entry, wrapped like the one it was
+// added with, keeping its schedule, status, last run and history. A run in
+// progress completes with the previous job.
+func (c *Cron) UpdateJob(name string, cmd Job) error {
+	return c.sendControl(controlRequest{op: controlUpdate, name: name, job: cmd})
+}
+
+func (c *Cron) sendControl(req controlRequest) error {
+	c.runningMu.Lock()
+	defer c.runningMu.Unlock()
+	if c.running {
+		req.reply = make(chan error, 1)
+		c.control <- req
+		return <-req.reply
+	}
+	return c.applyControl(req, c.now())
+}
+
+// applyControl is called from the scheduler goroutine while running, or with
+// runningMu held otherwise.
+func (c *Cron) applyControl(req controlRequest, now time.Time) error {
+	if req.cluster && c.clusterPaused != nil {
+		switch req.op {
+		case controlPause:
+			c.clusterPaused[req.name] = true
+		case controlResume, controlRemove:
+			delete(c.clusterPaused, req.name)
+		}
+	}
+	e := c.search(req.name)
+	if req.id != 0 {
+		e = c.searchID(req.id)
+	}
+	if e == nil {
+		return ErrNotFound
+	}
+	switch req.op {
+	case controlPause:
+		c.setStatus(e, StatusStopped)
+		e.logger.Info("pause")
+	case controlResume:
+		if c.paused(e) {
+			c.setStatus(e, StatusReady)
+		}
+		e.logger.Info("resume")
+	case controlTrigger:
+		if !c.running {
+			return ErrNotRunning
+		}
+		at := now
+		if !req.at.IsZero() {
+			at = req.at
+		}
+		e.logger.Info("trigger", "now", now, "at", at)
+		if !c.lock(e, at) {
+			return ErrLocked
+		}
+		c.setRunning(e)
+		c.startJob(e, []time.Time{at})
+		e.Prev = at
+	case controlRemove:
+		c.removeEntry(e.ID)
+		e.logger.Info("removed")
+	case controlDone:
+		if e.awaiting {
+			e.awaiting = false
+			e.Next = e.Schedule.Next(now)
+			logDebug(e.logger, "completed", "now", now, "next", e.Next)
+		}
+		return c.upstreamDone(e, req.at, req.err, now)
+	case controlFinished:
+		e.active--
+	case controlReschedule:
+		e.Schedule = e.spread(e.localize(req.schedule))
+		e.Spec = req.spec
+		e.missed = nil
+		if c.running {
+			e.Next = e.Schedule.Next(now)
+		}
+		e.logger.Info("rescheduled", "spec", req.spec, "next", e.Next)
+	case controlUpdate:
+		e.Job = req.job
+		e.WrappedJob = c.wrap(e, req.job)
+		e.logger.Info("updated")
+	}
+	return nil
+}
+
+func (c *Cron) setStatus(e *Entry, status int) {
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+	e.status = status
+}
+
+// setRunning marks e as running, unless it is paused.
+func (c *Cron) setRunning(e *Entry) {
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+	if e.status != StatusStopped {
+		e.status = StatusRunning
+	}
+}
+
+func (c *Cron) paused(e *Entry) bool {
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+	return e.status == StatusStopped
+}
+
+// lock takes the lock of the run of e scheduled at the given time, reporting
+// the attempt to the metrics.
+func (c *Cron) lock(e *Entry, at time.Time) bool {
+	err := e.getLock(at)
+	c.metrics.LockAcquired(e.Name, err == nil)
+	if err != nil {
+		c.metrics.RunSkipped(e.Name, SkipLocked)
+		switch {
+		case errors.Is(err, scron.ErrKeyLocked):
+			// another node took this activation
+			logDebug(e.logger, "lock failed", "at", at, "error", err)
+		case errors.Is(err, scron.ErrTaskLocked):
+			// the previous run is still going when the next one is due
+			logWarn(e.logger, "lock failed", "at", at, "error", err)
+		default:
+			e.logger.Error(err, "lock failed", "at", at)
+		}
+		return false
+	}
+	logDebug(e.logger, "locked", "at", at, "key", e.lockKey)
+	return true
+}
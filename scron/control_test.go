@@ -0,0 +1,72 @@
+package scron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPauseResume(t *testing.T) {
+	runs := make(chan struct{}, 10)
+	cron := newWithSeconds()
+	cron.AddSingleton("* * * * * ?", func() { runs <- struct{}{} }, "pause")
+	if err := cron.Pause("pause"); err != nil {
+		t.Fatal(err)
+	}
+	cron.Start()
+	defer cron.Stop()
+
+	select {
+	case <-runs:
+		t.Fatal("expected paused entry not to run")
+	case <-time.After(OneSecond):
+	}
+	if status := cron.Entries()[0].Status(); status != StatusStopped {
+		t.Errorf("expected status %d, got %d", StatusStopped, status)
+	}
+
+	if err := cron.Resume("pause"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-runs:
+	case <-time.After(OneSecond):
+		t.Error("expected resumed entry to run")
+	}
+}
+
+func TestTrigger(t *testing.T) {
+	runs := make(chan struct{}, 10)
+	cron := newWithSeconds()
+	cron.AddSingleton("@yearly", func() { runs <- struct{}{} }, "trigger")
+	if err := cron.Trigger("trigger"); err != ErrNotRunning {
+		t.Errorf("expected %v, got %v", ErrNotRunning, err)
+	}
+	cron.Start()
+	defer cron.Stop()
+
+	if err := cron.Trigger("trigger"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-runs:
+	case <-time.After(100 * time.Millisecond):
+		t.Error("expected triggered entry to run immediately")
+	}
+	if cron.Entries()[0].Prev.IsZero() {
+		t.Error("expected trigger to set Prev")
+	}
+}
+
+func TestControlNotFound(t *testing.T) {
+	cron := newWithSeconds()
+	for _, f := range []func(string) error{cron.Pause, cron.Resume, cron.Trigger} {
+		if err := f("missing"); err != ErrNotFound {
+			t.Errorf("expected %v, got %v", ErrNotFound, err)
+		}
+	}
+	cron.Start()
+	defer cron.Stop()
+	if err := cron.Pause("missing"); err != ErrNotFound {
+		t.Errorf("expected %v, got %v", ErrNotFound, err)
+	}
+}
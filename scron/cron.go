@@ -25,7 +25,7 @@ type Cron struct {
 	chain     Chain
 	stop      chan struct{}
 	add       chan *Entry
-	remove    chan EntryID
+	control   chan controlRequest
 	snapshot  chan chan []Entry
 	running   bool
 	logger    Logger
@@ -34,6 +34,19 @@ type Cron struct {
 	parser    ScheduleParser
 	nextID    EntryID
 	jobWaiter sync.WaitGroup
+	locks     LockProvider
+	ctx       context.Context
+	cancel    context.CancelFunc
+	hooks     []ResultHook
+	stateMu   sync.Mutex
+	history   HistoryStore
+	runs      RunStore
+	metrics   Metrics
+
+	controlChannel ControlChannel
+	// clusterPaused holds the names paused cluster-wide, as received on the
+	// ControlChannel. Owned by the scheduler goroutine.
+	clusterPaused map[string]bool
 }
 
 // ScheduleParser is an interface for schedule spec parsers that return a Schedule
@@ -46,6 +59,29 @@ type Job interface {
 	Run()
 }
 
+// ContextJob is an interface for submitted cron jobs that want to know when
+// to abort. The context is cancelled when the Cron is stopped, when the entry
+// is removed, or when the entry's timeout (see WithJobTimeout) elapses.
+type ContextJob interface {
+	Run(ctx context.Context) error
+}
+
+// contextRunner is implemented by jobs that accept the per-run context.
+// The wrappers in chain.go implement it so that the context reaches the
+// submitted ContextJob; wrappers that only call Run pass context.Background.
+type contextRunner interface {
+	RunContext(ctx context.Context) error
+}
+
+// runJob runs j with the given context if it accepts one.
+func runJob(ctx context.Context, j Job) error {
+	if r, ok := j.(contextRunner); ok {
+		return r.RunContext(ctx)
+	}
+	j.Run()
+	return nil
+}
+
 // Schedule describes a job's duty cycle.
 type Schedule interface {
 	// Next returns the next activation time, later than the given time.
@@ -83,11 +119,71 @@ type Entry struct {
 	// uniqe name
 	Name string
 
+	// Spec is the spec string the schedule was parsed from, empty when the
+	// Schedule was given directly.
+	Spec string
+
+	// After lists the entries that must complete successfully before this
+	// one runs, see AddAfter.
+	After []string
+
 	// Job status
 	status int
 
+	// LastResult is the outcome of the last finished run, with a zero End
+	// if the job never finished.
+	LastResult Result
+
+	// Runs and Failures count finished runs and those that returned an error.
+	Runs, Failures int
+
 	// Redis locker
 	Locker redis_locker.RedisLockInter
+
+	// timeout bounds a single run, zero means no deadline
+	timeout time.Duration
+
+	// retry is applied around the wrapped job, nil means no retries
+	retry *RetryPolicy
+
+	// wrappers are applied to the job inside the Cron's chain
+	wrappers []JobWrapper
+
+	// location and dst override those of a SpecSchedule, see localize
+	location *time.Location
+	dst      DSTPolicy
+
+	// jitter is the bound of the offset added to every activation, see spread
+	jitter time.Duration
+
+	// awaiting is set while a FixedDelaySchedule waits for its run to
+	// complete before computing Next
+	awaiting bool
+
+	// active counts the started runs not yet finished, see controlFinished,
+	// and neverMatches is set once a schedule without activations is reported
+	active       int
+	neverMatches bool
+
+	// lockDeadline is when the lock of the current run expires, and lockKey
+	// the key it was taken on
+	lockDeadline time.Time
+	lockKey      string
+
+	// logger adds the entry name to the Cron's logger
+	logger Logger
+
+	// misfire says how to catch up on missed activations, and missed holds
+	// those still to be run before Next
+	misfire MisfirePolicy
+	missed  []time.Time
+
+	// ctx is cancelled when the entry is removed or the Cron stopped
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// lockProvider hands out Locker for each run
+	lockProvider LockProvider
 }
 
 // Valid returns true if this is not the zero entry.
@@ -128,6 +224,14 @@ func (s byTime) Less(i, j int) bool {
 //	  Description: Wrap submitted jobs to customize behavior.
 //	  Default:     A chain that recovers panics and logs them to stderr.
 //
+//	Lock Provider
+//	  Description: Keeps an activation from running on more than one node.
+//	  Default:     Redis, via common.NewRedisClient
+//
+//	Run Store
+//	  Description: Keeps the last runs for misfire catch-up and AddAfter.
+//	  Default:     Redis with the Redis lock provider, in memory otherwise
+//
 // See "cron.With*" to modify the default behavior.
 func New(opts ...Option) *Cron {
 	c := &Cron{
@@ -136,16 +240,21 @@ func New(opts ...Option) *Cron {
 		add:       make(chan *Entry),
 		stop:      make(chan struct{}),
 		snapshot:  make(chan chan []Entry),
-		remove:    make(chan EntryID),
+		control:   make(chan controlRequest),
 		running:   false,
 		runningMu: sync.Mutex{},
 		logger:    DefaultLogger,
 		location:  time.Local,
 		parser:    standardParser,
+		locks:     RedisLockProvider(nil),
+		metrics:   discardMetrics{},
 	}
 	for _, opt := range opts {
 		opt(c)
 	}
+	if c.runs == nil {
+		c.runs = defaultRunStore(c.locks)
+	}
 	return c
 }
 
@@ -154,52 +263,103 @@ type FuncJob func()
 
 func (f FuncJob) Run() { f() }
 
+// FuncContextJob is a wrapper that turns a func(context.Context) error into a
+// cron.ContextJob
+type FuncContextJob func(ctx context.Context) error
+
+func (f FuncContextJob) Run(ctx context.Context) error { return f(ctx) }
+
+// contextFuncJob adapts a context-aware func to Job, so it can pass through
+// the Chain while still receiving the per-run context.
+type contextFuncJob func(ctx context.Context) error
+
+func (f contextFuncJob) Run() { f(context.Background()) }
+
+func (f contextFuncJob) RunContext(ctx context.Context) error { return f(ctx) }
+
 // AddSingleton adds a func to the Cron to be run on the given schedule.
 // The spec is parsed using the time zone of this Cron instance as the default.
 // An opaque ID is returned that can be used to later remove it.
-func (c *Cron) AddSingleton(spec string, cmd func(), cmdName string) (EntryID, error) {
-	return c.AddJob(spec, FuncJob(cmd), cmdName)
+func (c *Cron) AddSingleton(spec string, cmd func(), cmdName string, opts ...EntryOption) (EntryID, error) {
+	return c.AddJob(spec, FuncJob(cmd), cmdName, opts...)
 }
 
 // AddJob adds a Job to the Cron to be run on the given schedule.
 // The spec is parsed using the time zone of this Cron instance as the default.
 // An opaque ID is returned that can be used to later remove it.
-func (c *Cron) AddJob(spec string, cmd Job, cmdName string) (EntryID, error) {
+func (c *Cron) AddJob(spec string, cmd Job, cmdName string, opts ...EntryOption) (EntryID, error) {
 	schedule, err := c.parser.Parse(spec)
 	if err != nil {
 		return 0, err
 	}
-	return c.Schedule(schedule, cmd, cmdName), nil
+	return c.Schedule(schedule, cmd, cmdName, append([]EntryOption{withSpec(spec)}, opts...)...)
+}
+
+// AddContextJob adds a ContextJob to the Cron to be run on the given schedule.
+// The spec is parsed using the time zone of this Cron instance as the default.
+// An opaque ID is returned that can be used to later remove it.
+func (c *Cron) AddContextJob(spec string, cmd ContextJob, cmdName string, opts ...EntryOption) (EntryID, error) {
+	return c.AddJob(spec, contextFuncJob(cmd.Run), cmdName, opts...)
+}
+
+// ScheduleContext adds a ContextJob to the Cron to be run on the given
+// schedule. The job is wrapped with the configured Chain.
+func (c *Cron) ScheduleContext(schedule Schedule, cmd ContextJob, cmdName string, opts ...EntryOption) (EntryID, error) {
+	return c.Schedule(schedule, contextFuncJob(cmd.Run), cmdName, opts...)
 }
 
 // Schedule adds a Job to the Cron to be run on the given schedule.
 // The job is wrapped with the configured Chain.
-func (c *Cron) Schedule(schedule Schedule, cmd Job, cmdName string) EntryID {
+// ErrDuplicateName is returned if an entry already has the name.
+func (c *Cron) Schedule(schedule Schedule, cmd Job, cmdName string, opts ...EntryOption) (EntryID, error) {
 	c.runningMu.Lock()
 	defer c.runningMu.Unlock()
-	c.nextID++
+	// search Repeat AddJob
+	entries := c.snapshotEntries()
+	if hasName(entries, cmdName) {
+		return 0, ErrDuplicateName
+	}
 	entry := &Entry{
-		ID:         c.nextID,
-		Schedule:   schedule,
-		WrappedJob: c.chain.Then(cmd),
-		Job:        cmd,
-		Name:       cmdName,
+		Schedule: schedule,
+		Job:      cmd,
+		Name:     cmdName,
+
+		lockProvider: c.locks,
+		logger:       logWith(c.logger, "entry", cmdName),
 	}
-	// search Repeat AddJob
-	if c.check(cmdName) {
-		return 0
+	for _, opt := range opts {
+		opt(entry)
 	}
+	// Adds are serialized by runningMu, so no other entry can close the
+	// cycle between this check and the insert.
+	if err := checkCycle(entries, cmdName, entry.After); err != nil {
+		return 0, err
+	}
+	c.nextID++
+	entry.ID = c.nextID
+	entry.Schedule = entry.spread(entry.localize(schedule))
+	entry.WrappedJob = c.wrap(entry, cmd)
 	if !c.running {
 		c.entries = append(c.entries, entry)
 	} else {
 		c.add <- entry
 	}
-	return entry.ID
+	return entry.ID, nil
 }
 
-// Cron entries check
-func (c *Cron) check(name string) bool {
-	for _, item := range c.entries {
+// wrap applies the entry's wrappers, the Cron's chain and the entry's retry
+// policy to cmd.
+func (c *Cron) wrap(e *Entry, cmd Job) Job {
+	job := c.chain.Then(NewChain(e.wrappers...).Then(cmd))
+	if e.retry != nil {
+		job = Retry(e.logger, *e.retry)(job)
+	}
+	return job
+}
+
+// hasName reports whether one of the entries has the given name.
+func hasName(entries []Entry, name string) bool {
+	for _, item := range entries {
 		if item.Name == name {
 			return true
 		}
@@ -207,6 +367,16 @@ func (c *Cron) check(name string) bool {
 	return false
 }
 
+// Cron entries search by ID
+func (c *Cron) searchID(id EntryID) *Entry {
+	for _, item := range c.entries {
+		if item.ID == id {
+			return item
+		}
+	}
+	return nil
+}
+
 // Cron entries search
 func (c *Cron) search(name string) *Entry {
 	for _, item := range c.entries {
@@ -221,6 +391,12 @@ func (c *Cron) search(name string) *Entry {
 func (c *Cron) Entries() []Entry {
 	c.runningMu.Lock()
 	defer c.runningMu.Unlock()
+	return c.snapshotEntries()
+}
+
+// snapshotEntries returns a snapshot of the entries, asking the scheduler if
+// it is running. runningMu must be held.
+func (c *Cron) snapshotEntries() []Entry {
 	if c.running {
 		replyChan := make(chan []Entry, 1)
 		c.snapshot <- replyChan
@@ -244,32 +420,28 @@ func (c *Cron) Entry(id EntryID) Entry {
 	return Entry{}
 }
 
-// Remove an entry from being run in the future.
-func (c *Cron) Remove(id EntryID) {
-	c.runningMu.Lock()
-	defer c.runningMu.Unlock()
-	if c.running {
-		c.remove <- id
-	} else {
-		c.removeEntry(id)
+// EntryByName returns a snapshot of the named entry, or ErrNotFound.
+func (c *Cron) EntryByName(name string) (Entry, error) {
+	for _, entry := range c.Entries() {
+		if entry.Name == name {
+			return entry, nil
+		}
 	}
+	return Entry{}, ErrNotFound
+}
+
+// Remove an entry from being run in the future.
+// ErrNotFound is returned if no entry has the ID.
+// The fencing counter of the entry in Redis is kept (see FencingToken), so
+// that an entry added again under the same name never reuses a token.
+func (c *Cron) Remove(id EntryID) error {
+	return c.sendControl(controlRequest{op: controlRemove, id: id})
 }
 
 // RemoveByName an entry from being run in the future.
-func (c *Cron) RemoveByName(name string) {
-	c.runningMu.Lock()
-	defer c.runningMu.Unlock()
-	var id EntryID
-	entry := c.search(name)
-	if entry == nil && entry.ID <= 0 {
-		return
-	}
-	id = entry.ID
-	if c.running {
-		c.remove <- id
-	} else {
-		c.removeEntry(id)
-	}
+// ErrNotFound is returned if no entry has the name.
+func (c *Cron) RemoveByName(name string) error {
+	return c.sendControl(controlRequest{op: controlRemove, name: name})
 }
 
 // Start the cron scheduler in its own goroutine, or no-op if already started.
@@ -299,15 +471,25 @@ func (c *Cron) Run() {
 // access to the 'running' state variable.
 func (c *Cron) run() {
 	c.logger.Info("start")
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+	c.clusterPaused = make(map[string]bool)
+	if c.controlChannel != nil {
+		go c.listen(c.ctx)
+	}
 
 	// Figure out the next activation times for each entry.
 	now := c.now()
 	for _, entry := range c.entries {
+		entry.ctx, entry.cancel = context.WithCancel(c.ctx)
+		entry.awaiting = false
 		entry.Next = entry.Schedule.Next(now)
-		c.logger.Info("schedule", "now", now, "entry", entry.ID, "next", entry.Next)
+		c.catchUp(entry, now)
+		logDebug(entry.logger, "schedule", "now", now, "next", entry.Next)
 	}
 
 	for {
+		c.removeExhausted()
+
 		// Determine the next entry to run.
 		sort.Sort(byTime(c.entries))
 
@@ -324,30 +506,51 @@ func (c *Cron) run() {
 			select {
 			case now = <-timer.C:
 				now = now.In(c.location)
-				c.logger.Info("wake", "now", now)
+				logDebug(c.logger, "wake", "now", now)
 
 				// Run every entry whose next time was less than now
 				for _, e := range c.entries {
 					if e.Next.After(now) || e.Next.IsZero() {
 						break
 					}
-					c.logger.Info("start", "now", now, "entry", e.Name, "next", e.Next)
+					c.metrics.SchedulerLag(e.Name, now.Sub(e.Next))
+					if c.paused(e) {
+						c.metrics.RunSkipped(e.Name, SkipPaused)
+						e.missed = nil
+						e.Next = e.Schedule.Next(now)
+						e.logger.Info("paused", "now", now, "next", e.Next)
+						continue
+					}
+					logDebug(e.logger, "start", "now", now, "next", e.Next)
+					activations := e.activations(now)
 					// 加锁失败 跳过执行
-					if e.getLock() {
-						e.status = StatusRunning
-						c.startJob(e)
-						e.Prev = e.Next
+					if c.lock(e, e.Next) {
+						c.setRunning(e)
+						c.startJob(e, activations)
+						e.Prev = activations[len(activations)-1]
+						c.markRun(e, e.Prev)
+						if fixedDelay(e.Schedule) {
+							// Scheduled again once the run completes, see controlDone.
+							e.awaiting = true
+							e.Next = time.Time{}
+							continue
+						}
 					}
 					e.Next = e.Schedule.Next(now)
-					c.logger.Info("run", "now", now, "entry", e.ID, "next", e.Next)
+					logDebug(e.logger, "run", "now", now, "next", e.Next)
 				}
 
 			case newEntry := <-c.add:
 				timer.Stop()
 				now = c.now()
+				newEntry.ctx, newEntry.cancel = context.WithCancel(c.ctx)
+				if c.clusterPaused[newEntry.Name] {
+					c.setStatus(newEntry, StatusStopped)
+				}
 				newEntry.Next = newEntry.Schedule.Next(now)
+				c.catchUp(newEntry, now)
 				c.entries = append(c.entries, newEntry)
-				c.logger.Info("added", "now", now, "entry", newEntry.ID, "next", newEntry.Next)
+				newEntry.logger.Info("added", "now", now, "next", newEntry.Next)
 
 			case replyChan := <-c.snapshot:
 				replyChan <- c.entrySnapshot()
@@ -355,14 +558,14 @@ func (c *Cron) run() {
 
 			case <-c.stop:
 				timer.Stop()
+				c.cancel()
 				c.logger.Info("stop")
 				return
 
-			case id := <-c.remove:
+			case req := <-c.control:
 				timer.Stop()
 				now = c.now()
-				c.removeEntry(id)
-				c.logger.Info("removed", "entry", id)
+				req.reply <- c.applyControl(req, now)
 			}
 
 			break
@@ -370,16 +573,59 @@ func (c *Cron) run() {
 	}
 }
 
-// startJob runs the given job in a new goroutine.
-func (c *Cron) startJob(e *Entry) {
+// startJob runs the given job in a new goroutine, once for every activation.
+func (c *Cron) startJob(e *Entry, activations []time.Time) {
+	var (
+		entryCtx     = e.ctx
+		timeout      = e.timeout
+		lockDeadline = e.lockDeadline
+		locker       = e.Locker
+		lockKey      = e.lockKey
+		fence        = fencingToken(e.Locker)
+		job          = e.WrappedJob
+	)
+	e.active++
 	c.jobWaiter.Add(1)
 	go func() {
 		defer func() {
 			c.jobWaiter.Done()
-			e.status = StatusReady
-			e.releaseLock()
+			c.stateMu.Lock()
+			if e.status == StatusRunning {
+				e.status = StatusReady
+			}
+			c.stateMu.Unlock()
+			e.releaseLock(locker)
+			c.sendControl(controlRequest{op: controlFinished, id: e.ID})
 		}()
-		e.Job.Run()
+		for _, scheduled := range activations {
+			if entryCtx.Err() != nil {
+				return
+			}
+			var (
+				ctx    context.Context
+				cancel context.CancelFunc
+			)
+			if timeout > 0 {
+				ctx, cancel = context.WithTimeout(entryCtx, timeout)
+			} else {
+				ctx, cancel = context.WithCancel(entryCtx)
+			}
+			if !lockDeadline.IsZero() {
+				ctx = context.WithValue(ctx, lockDeadlineKey{}, lockDeadline)
+			}
+			if fence > 0 {
+				ctx = context.WithValue(ctx, fencingTokenKey{}, fence)
+			}
+			runID := newRunID()
+			ctx = context.WithValue(ctx, runInfoKey{}, RunInfo{Entry: e.ID, Name: e.Name, RunID: runID, Scheduled: scheduled, LockKey: lockKey, FencingToken: fence})
+			logDebug(e.logger, "job start", "run", runID, "scheduled", scheduled)
+			result := Result{Entry: e.ID, Name: e.Name, RunID: runID, Scheduled: scheduled, Start: c.now()}
+			result.Err = runJob(ctx, job)
+			result.End = c.now()
+			cancel()
+			logDebug(e.logger, "job end", "run", runID, "duration", result.Duration())
+			c.finish(e, result)
+		}
 	}()
 }
 
@@ -389,6 +635,7 @@ func (c *Cron) now() time.Time {
 }
 
 // Stop stops the cron scheduler if it is running; otherwise it does nothing.
+// The context passed to running ContextJobs is cancelled.
 // A context is returned so the caller can wait for running jobs to complete.
 func (c *Cron) Stop() context.Context {
 	c.runningMu.Lock()
@@ -407,6 +654,8 @@ func (c *Cron) Stop() context.Context {
 
 // entrySnapshot returns a copy of the current cron entry list.
 func (c *Cron) entrySnapshot() []Entry {
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
 	var entries = make([]Entry, len(c.entries))
 	for i, e := range c.entries {
 		entries[i] = *e
@@ -414,11 +663,43 @@ func (c *Cron) entrySnapshot() []Entry {
 	return entries
 }
 
+// removeExhausted removes the entries whose finite schedule has no activation
+// left, e.g. OnceAt once it has run, after their last run has completed.
+// Their context is cancelled as by Remove. Dependent entries never activate on
+// their own and are kept, as are entries whose spec never matches, which are
+// reported once at Warn.
+func (c *Cron) removeExhausted() {
+	var entries []*Entry
+	for _, e := range c.entries {
+		exhausted := e.Next.IsZero() && len(e.After) == 0 && !e.awaiting
+		if exhausted && !finite(e.Schedule) {
+			if !e.neverMatches {
+				logWarn(e.logger, "schedule never matches", "spec", e.Spec)
+			}
+			e.neverMatches = true
+			entries = append(entries, e)
+			continue
+		}
+		e.neverMatches = false
+		if exhausted && e.active == 0 {
+			if e.cancel != nil {
+				e.cancel()
+			}
+			e.logger.Info("exhausted", "prev", e.Prev)
+			continue
+		}
+		entries = append(entries, e)
+	}
+	c.entries = entries
+}
+
 func (c *Cron) removeEntry(id EntryID) {
 	var entries []*Entry
 	for _, e := range c.entries {
 		if e.ID != id {
 			entries = append(entries, e)
+		} else if e.cancel != nil {
+			e.cancel()
 		}
 	}
 	c.entries = entries
@@ -1,9 +1,17 @@
 package scron
 
 import (
+	"errors"
 	"time"
 )
 
+var (
+	// ErrKeyLocked 执行期key已存在，本次执行已被其他节点获取
+	ErrKeyLocked = errors.New("lock key failed")
+	// ErrTaskLocked 任务key已存在，上一次执行尚未结束
+	ErrTaskLocked = errors.New("lock Taskkey failed")
+)
+
 type RedisLockInter interface {
 	// Lock 加锁
 	Lock() error
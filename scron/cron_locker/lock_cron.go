@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/go-redis/redis/v8"
+	"github.com/henryxu/tools/redis_locker"
 	"log"
 	"sync"
 	"time"
@@ -12,12 +13,13 @@ import (
 
 type CronLock struct {
 	context.Context
-	*redis.Client
+	redis.UniversalClient
 	key             string
 	Taskkey         string
 	token           string
 	lockTimeout     time.Duration
 	isAutoRenew     bool
+	fence           int64
 	autoRenewCtx    context.Context
 	autoRenewCancel context.CancelFunc
 	mutex           sync.Mutex
@@ -27,11 +29,11 @@ type CronLock struct {
 
 type Option func(lock *CronLock)
 
-func NewCronLock(ctx context.Context, redisClient *redis.Client, lockKey, taskKey string, options ...Option) RedisLockInter {
+func NewCronLock(ctx context.Context, redisClient redis.UniversalClient, lockKey, taskKey string, options ...Option) RedisLockInter {
 	lock := &CronLock{
-		Context:     ctx,
-		Client:      redisClient,
-		lockTimeout: lockTime,
+		Context:         ctx,
+		UniversalClient: redisClient,
+		lockTimeout:     lockTime,
 	}
 	for _, f := range options {
 		f(lock)
@@ -75,18 +77,42 @@ func WithToken(token string) Option {
 	}
 }
 
-// Lock 加锁
+// taskExtra 任务key比执行期key多保留的时间
+const taskExtra = 2 * time.Second
+
+// lockScript、taskLockScript加锁失败的返回值，成功时返回fencing token(>=1)
+const (
+	lockKeyTaken  = -1 // 见ErrKeyLocked
+	lockTaskTaken = -2 // 见ErrTaskLocked
+)
+
+// Lock 加锁，执行期key和任务key在一个脚本中原子设置，失败时不会残留任何key。
+// 成功时签发任务的fencing token，见FencingToken
 func (lock *CronLock) Lock() error {
 	lock.mutex.Lock()
 	defer lock.mutex.Unlock()
-	result, err := lock.Client.SetNX(lock.Context, lock.key, lock.token, time.Duration(lock.lockTimeout.Seconds())*time.Second).Result()
-	if !result || err != nil {
-		return errors.New("lock key failed")
+	var (
+		result int64
+		err    error
+	)
+	if _, ok := lock.UniversalClient.(*redis.ClusterClient); ok {
+		result, err = lock.lockCluster()
+	} else {
+		result, err = lockScript.Run(lock.Context, lock.UniversalClient,
+			[]string{lock.key, lock.Taskkey, redis_locker.FenceKey(lock.Taskkey)},
+			lock.token, milliseconds(lock.lockTimeout), taskExtra.Milliseconds()).Int64()
+	}
+	if err != nil {
+		// redis出错，不是锁被占用
+		return fmt.Errorf("failed to lock: %w", err)
 	}
-	result, err = lock.Client.SetNX(lock.Context, lock.Taskkey, lock.token, time.Duration(lock.lockTimeout.Seconds())*time.Second+2).Result()
-	if !result || err != nil {
-		return errors.New("lock Taskkey failed")
+	switch result {
+	case lockKeyTaken:
+		return ErrKeyLocked
+	case lockTaskTaken:
+		return ErrTaskLocked
 	}
+	lock.fence = result
 	if lock.isAutoRenew {
 		lock.autoRenewCtx, lock.autoRenewCancel = context.WithCancel(lock.Context)
 		go lock.autoRenew()
@@ -94,6 +120,36 @@ func (lock *CronLock) Lock() error {
 	return nil
 }
 
+// lockCluster Redis Cluster下两个key通常不在同一个slot，无法在一个脚本中设置，
+// 改为依次设置，任务key失败时删除已设置的执行期key。返回值同lockScript，redis出错时返回error
+func (lock *CronLock) lockCluster() (int64, error) {
+	ttl := time.Duration(milliseconds(lock.lockTimeout)) * time.Millisecond
+	ok, err := lock.UniversalClient.SetNX(lock.Context, lock.key, lock.token, ttl).Result()
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return lockKeyTaken, nil
+	}
+	fence, err := taskLockScript.Run(lock.Context, lock.UniversalClient,
+		[]string{lock.Taskkey, redis_locker.FenceKey(lock.Taskkey)},
+		lock.token, (ttl + taskExtra).Milliseconds()).Int64()
+	if fence < 0 || err != nil {
+		unLockScript.Run(lock.Context, lock.UniversalClient, []string{lock.key}, lock.token)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return fence, nil
+}
+
+// FencingToken 本次加锁签发的token，每个任务单调递增，未加锁时为0
+func (lock *CronLock) FencingToken() int64 {
+	lock.mutex.Lock()
+	defer lock.mutex.Unlock()
+	return lock.fence
+}
+
 // UnLock 解锁
 func (lock *CronLock) UnLock() error {
 	lock.mutex.Lock()
@@ -103,19 +159,10 @@ func (lock *CronLock) UnLock() error {
 	if lock.autoRenewCancel != nil {
 		lock.autoRenewCancel()
 	}
-	if lock.Client.Get(lock.Context, lock.Taskkey).Val() == lock.token {
-		if err := lock.Client.Del(lock.Context, lock.Taskkey).Err(); err != nil {
-			return fmt.Errorf("failed to remove lock: %s", err)
-		}
+	// 只删除任务key，执行期key保留到过期，防止同一执行期重复执行
+	if err := unLockScript.Run(lock.Context, lock.UniversalClient, []string{lock.Taskkey}, lock.token).Err(); err != nil {
+		return fmt.Errorf("failed to remove lock: %s", err)
 	}
-	//result, err := lock.Client.Eval(lock.Context, unLockScript, []string{lock.Taskkey}, lock.token).Result()
-	//if err != nil {
-	//	return fmt.Errorf("failed to release lock: %w", err)
-	//}
-	//if result != "OK" {
-	//	return errors.New("lock release failed")
-	//}
-
 	return nil
 }
 
@@ -147,20 +194,25 @@ func (lock *CronLock) SpinLock(timeout time.Duration) error {
 func (lock *CronLock) Renew() error {
 	lock.mutex.Lock()
 	defer lock.mutex.Unlock()
-	//res, err := lock.Client.Eval(lock.Context, renewScript, []string{lock.key}, lock.token, lock.lockTimeout.Seconds()).Result()
-	//if err == redis.Nil {
-	//	return nil
-	//}
-	if lock.Client.Get(lock.Context, lock.Taskkey).Val() == lock.token {
-		if err := lock.Client.Expire(lock.Context, lock.Taskkey, time.Duration(lock.lockTimeout.Seconds()/3*2)*time.Second).Err(); err != nil {
-			return fmt.Errorf("failed to renew lock: %s", err)
-		}
-	} else {
+	result, err := renewScript.Run(lock.Context, lock.UniversalClient, []string{lock.Taskkey},
+		lock.token, milliseconds(lock.lockTimeout/3*2)).Int()
+	if err != nil {
+		return fmt.Errorf("failed to renew lock: %s", err)
+	}
+	if result != 1 {
 		return fmt.Errorf("failed to renew lock:")
 	}
 	return nil
 }
 
+// milliseconds 过期时间转为毫秒，至少1毫秒
+func milliseconds(d time.Duration) int64 {
+	if ms := d.Milliseconds(); ms > 0 {
+		return ms
+	}
+	return 1
+}
+
 // 锁自动续期
 func (lock *CronLock) autoRenew() {
 	ticker := time.NewTicker(lock.lockTimeout / 2)
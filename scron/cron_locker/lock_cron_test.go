@@ -0,0 +1,220 @@
+package scron
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+func newTestLock(client redis.UniversalClient, token string) *CronLock {
+	return NewCronLock(context.Background(), client, "cron_job20240101000000", "exec_job",
+		WithTimeout(10*time.Second), WithToken(token)).(*CronLock)
+}
+
+func TestCronLock(t *testing.T) {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+
+	lock := newTestLock(client, "a")
+	if err := lock.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := s.Get("cron_job20240101000000"); v != "a" {
+		t.Errorf("expected the key owned by a, got %q", v)
+	}
+	if ttl := s.TTL("exec_job"); ttl != 12*time.Second {
+		t.Errorf("expected the task key to outlive the key by 2s, got %v", ttl)
+	}
+	if ok, _ := client.ScriptExists(context.Background(), lockScript.Hash()).Result(); len(ok) != 1 || !ok[0] {
+		t.Error("expected the lock script cached for EVALSHA")
+	}
+
+	other := newTestLock(client, "b")
+	if err := other.Lock(); err == nil || err.Error() != "lock key failed" {
+		t.Errorf("expected the key taken, got %v", err)
+	}
+	if err := other.Renew(); err == nil {
+		t.Error("expected renewing a lock held by another token to fail")
+	}
+	if err := other.UnLock(); err != nil || !s.Exists("exec_job") {
+		t.Errorf("expected unlocking by another token to keep the lock, got %v", err)
+	}
+
+	s.FastForward(5 * time.Second)
+	if err := lock.Renew(); err != nil {
+		t.Fatal(err)
+	}
+	if ttl := s.TTL("exec_job"); ttl <= 6*time.Second {
+		t.Errorf("expected the task key renewed, got %v", ttl)
+	}
+
+	if err := lock.UnLock(); err != nil {
+		t.Fatal(err)
+	}
+	if s.Exists("exec_job") || !s.Exists("cron_job20240101000000") {
+		t.Errorf("expected only the task key released, got %v", s.Keys())
+	}
+}
+
+func TestCronLockNoOrphanedKey(t *testing.T) {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	s.Set("exec_job", "running")
+
+	err := newTestLock(client, "a").Lock()
+	if err == nil || err.Error() != "lock Taskkey failed" {
+		t.Fatalf("expected the task key taken, got %v", err)
+	}
+	if s.Exists("cron_job20240101000000") {
+		t.Error("expected no key left behind by a failed lock")
+	}
+}
+
+func TestCronLockConcurrentNodes(t *testing.T) {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr(), PoolSize: 20})
+
+	// The previous run still holds the task key for some activations.
+	for round := 0; round < 20; round++ {
+		key := "cron_job" + time.Date(2024, 1, 1, 0, 0, round, 0, time.UTC).Format("20060102150405")
+		busy := round%2 == 1
+		if busy {
+			s.Set("exec_job", "previous")
+		}
+		var (
+			wg     sync.WaitGroup
+			mu     sync.Mutex
+			owners []string
+		)
+		for node := 0; node < 10; node++ {
+			wg.Add(1)
+			go func(token string) {
+				defer wg.Done()
+				lock := NewCronLock(context.Background(), client, key, "exec_job", WithTimeout(10*time.Second), WithToken(token))
+				if lock.Lock() == nil {
+					mu.Lock()
+					owners = append(owners, token)
+					mu.Unlock()
+				}
+			}(string(rune('a' + node)))
+		}
+		wg.Wait()
+
+		if busy {
+			if len(owners) != 0 || s.Exists(key) {
+				t.Fatalf("round %d: expected no owner and no orphaned key, got %v and %v", round, owners, s.Keys())
+			}
+		} else {
+			if len(owners) != 1 {
+				t.Fatalf("round %d: expected exactly one owner, got %v", round, owners)
+			}
+			if v, _ := s.Get(key); v != owners[0] {
+				t.Fatalf("round %d: expected the key owned by %s, got %q", round, owners[0], v)
+			}
+			if v, _ := s.Get("exec_job"); v != owners[0] {
+				t.Fatalf("round %d: expected the task key owned by %s, got %q", round, owners[0], v)
+			}
+		}
+		s.Del("exec_job")
+	}
+}
+
+func TestCronLockCluster(t *testing.T) {
+	s := miniredis.RunT(t)
+	client := redis.NewClusterClient(&redis.ClusterOptions{Addrs: []string{s.Addr()}})
+	s.Set("exec_job", "running")
+
+	if err := newTestLock(client, "a").Lock(); err == nil || err.Error() != "lock Taskkey failed" {
+		t.Fatalf("expected the task key taken, got %v", err)
+	}
+	if s.Exists("cron_job20240101000000") {
+		t.Error("expected the key rolled back")
+	}
+
+	s.Del("exec_job")
+	if err := newTestLock(client, "a").Lock(); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := s.Get("exec_job"); v != "a" {
+		t.Errorf("expected the task key owned by a, got %q", v)
+	}
+}
+
+func TestCronLockFencing(t *testing.T) {
+	for name, client := range map[string]func(addr string) redis.UniversalClient{
+		"single": func(addr string) redis.UniversalClient { return redis.NewClient(&redis.Options{Addr: addr}) },
+		"cluster": func(addr string) redis.UniversalClient {
+			return redis.NewClusterClient(&redis.ClusterOptions{Addrs: []string{addr}})
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			s := miniredis.RunT(t)
+			c := client(s.Addr())
+
+			var prev int64
+			for i := 0; i < 3; i++ {
+				key := "cron_job" + time.Date(2024, 1, 1, 0, 0, i, 0, time.UTC).Format("20060102150405")
+				lock := NewCronLock(context.Background(), c, key, "exec_job", WithTimeout(10*time.Second)).(*CronLock)
+				if err := lock.Lock(); err != nil {
+					t.Fatal(err)
+				}
+				if lock.FencingToken() <= prev {
+					t.Fatalf("expected the token to increase past %d, got %d", prev, lock.FencingToken())
+				}
+				prev = lock.FencingToken()
+
+				other := NewCronLock(context.Background(), c, key, "exec_job", WithTimeout(10*time.Second)).(*CronLock)
+				if other.Lock() == nil || other.FencingToken() != 0 {
+					t.Fatalf("expected a failed lock to issue no token, got %d", other.FencingToken())
+				}
+				if err := lock.UnLock(); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if v, _ := s.Get("{exec_job}:fence"); v != "3" {
+				t.Errorf("expected failed locks not to consume tokens, got %q", v)
+			}
+		})
+	}
+}
+
+func TestLocalLockFencing(t *testing.T) {
+	store := NewLocalStore()
+	first := NewLocalLocker(store, "cron_job20240101000000", "exec_job", 60).(*LocalLock)
+	if err := first.Lock(); err != nil || first.FencingToken() != 1 {
+		t.Fatalf("expected token 1, got %d and %v", first.FencingToken(), err)
+	}
+	if NewLocalLocker(store, "cron_job20240101000001", "exec_job", 60).Lock() == nil {
+		t.Fatal("expected the task key taken")
+	}
+	first.UnLock()
+	second := NewLocalLocker(store, "cron_job20240101000001", "exec_job", 60).(*LocalLock)
+	if err := second.Lock(); err != nil || second.FencingToken() != 2 {
+		t.Fatalf("expected token 2, got %d and %v", second.FencingToken(), err)
+	}
+}
+
+func TestCronLockRedisError(t *testing.T) {
+	for name, client := range map[string]func(addr string) redis.UniversalClient{
+		"single": func(addr string) redis.UniversalClient { return redis.NewClient(&redis.Options{Addr: addr}) },
+		"cluster": func(addr string) redis.UniversalClient {
+			return redis.NewClusterClient(&redis.ClusterOptions{Addrs: []string{addr}})
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			s := miniredis.RunT(t)
+			c := client(s.Addr())
+			s.SetError("LOADING Redis is loading the dataset in memory")
+
+			err := newTestLock(c, "a").Lock()
+			if err == nil || errors.Is(err, ErrKeyLocked) || errors.Is(err, ErrTaskLocked) {
+				t.Errorf("expected a redis error, not contention, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package scron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// LocalStore 进程内的锁存储，用于单机部署或单元测试
+type LocalStore struct {
+	mutex  sync.Mutex
+	keys   map[string]localItem
+	fences map[string]int64
+}
+
+type localItem struct {
+	token    string
+	expireAt time.Time
+}
+
+func NewLocalStore() *LocalStore {
+	return &LocalStore{keys: make(map[string]localItem), fences: make(map[string]int64)}
+}
+
+// get 获取未过期的key
+func (s *LocalStore) get(key string, now time.Time) (localItem, bool) {
+	item, ok := s.keys[key]
+	if !ok {
+		return item, false
+	}
+	if !item.expireAt.After(now) {
+		delete(s.keys, key)
+		return item, false
+	}
+	return item, true
+}
+
+// setBothNX 同时设置key和taskKey，任意一个已存在则都不设置，成功时返回taskKey的fencing token
+func (s *LocalStore) setBothNX(key, taskKey, token string, ttl time.Duration) (int64, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	now := time.Now()
+	if _, ok := s.get(key, now); ok {
+		return 0, ErrKeyLocked
+	}
+	if _, ok := s.get(taskKey, now); ok {
+		return 0, ErrTaskLocked
+	}
+	s.keys[key] = localItem{token: token, expireAt: now.Add(ttl)}
+	s.keys[taskKey] = localItem{token: token, expireAt: now.Add(ttl + taskExtra)}
+	s.fences[taskKey]++
+	return s.fences[taskKey], nil
+}
+
+// Owner 获取key当前的token，未加锁时为空
+func (s *LocalStore) Owner(key string) string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if item, ok := s.get(key, time.Now()); ok {
+		return item.token
+	}
+	return ""
+}
+
+// delIfOwner token一致时删除key
+func (s *LocalStore) delIfOwner(key, token string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if item, ok := s.get(key, time.Now()); ok && item.token == token {
+		delete(s.keys, key)
+		return true
+	}
+	return false
+}
+
+// expireIfOwner token一致时重置过期时间
+func (s *LocalStore) expireIfOwner(key, token string, ttl time.Duration) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	now := time.Now()
+	if item, ok := s.get(key, now); ok && item.token == token {
+		item.expireAt = now.Add(ttl)
+		s.keys[key] = item
+		return true
+	}
+	return false
+}
+
+type LocalLock struct {
+	context.Context
+	store       *LocalStore
+	key         string
+	Taskkey     string
+	token       string
+	lockTimeout time.Duration
+	fence       int64
+	mutex       sync.Mutex
+}
+
+// NewLocalLocker 进程内锁，语义与CronLock一致
+func NewLocalLocker(store *LocalStore, key, taskKey string, ttl int) RedisLockInter {
+	return &LocalLock{
+		Context:     context.Background(),
+		store:       store,
+		key:         key,
+		Taskkey:     taskKey,
+		token:       fmt.Sprintf("token_%d", time.Now().UnixNano()),
+		lockTimeout: time.Duration(ttl) * time.Second,
+	}
+}
+
+// Lock 加锁
+func (lock *LocalLock) Lock() error {
+	lock.mutex.Lock()
+	defer lock.mutex.Unlock()
+	fence, err := lock.store.setBothNX(lock.key, lock.Taskkey, lock.token, lock.lockTimeout)
+	if err != nil {
+		return err
+	}
+	lock.fence = fence
+	return nil
+}
+
+// FencingToken 本次加锁签发的token，同一taskKey单调递增，未加锁时为0
+func (lock *LocalLock) FencingToken() int64 {
+	lock.mutex.Lock()
+	defer lock.mutex.Unlock()
+	return lock.fence
+}
+
+// UnLock 解锁，key保留到过期，防止同一执行期重复执行
+func (lock *LocalLock) UnLock() error {
+	lock.mutex.Lock()
+	defer lock.mutex.Unlock()
+	lock.store.delIfOwner(lock.Taskkey, lock.token)
+	return nil
+}
+
+// SpinLock 自旋锁
+func (lock *LocalLock) SpinLock(timeout time.Duration) error {
+	exp := time.Now().Add(timeout)
+	for {
+		if time.Now().After(exp) {
+			return errors.New("spin lock timeout")
+		}
+		if err := lock.Lock(); err == nil {
+			return nil
+		}
+		select {
+		case <-lock.Context.Done():
+			return lock.Context.Err()
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+// Renew 锁手动续期
+func (lock *LocalLock) Renew() error {
+	lock.mutex.Lock()
+	defer lock.mutex.Unlock()
+	if !lock.store.expireIfOwner(lock.Taskkey, lock.token, lock.lockTimeout) {
+		return fmt.Errorf("failed to renew lock:")
+	}
+	return nil
+}
@@ -1,45 +1,59 @@
 package scron
 
-const (
-	// 加锁
-	lockScript = `
+import "github.com/go-redis/redis/v8"
+
+// 脚本通过EVALSHA执行，服务端未缓存时自动回退为EVAL
+var (
+	// 加锁：执行期key和任务key同时设置，任意一个已存在则都不设置，成功时对任务的
+	// fencing计数器INCR。返回fencing token，lockKeyTaken执行期key已存在，lockTaskTaken任务key已存在
+	lockScript = redis.NewScript(`
 		local lock_key = KEYS[1]
 		local lock_task = KEYS[2]
+		local fence_key = KEYS[3]
 		local lock_value = ARGV[1]
 		local lock_ttl = tonumber(ARGV[2])
-		if redis.call('SET', lock_task, lock_value, 'NX', 'EX', lock_ttl + 3) then
-			redis.call('SET', lock_key, '1', 'NX', 'EX', lock_ttl) 
-			return "OK"
+		local task_extra = tonumber(ARGV[3])
+		if redis.call('EXISTS', lock_key) == 1 then
+			return -1
+		end
+		if redis.call('EXISTS', lock_task) == 1 then
+			return -2
 		end
-		return nil
-	`
+		redis.call('SET', lock_key, lock_value, 'PX', lock_ttl)
+		redis.call('SET', lock_task, lock_value, 'PX', lock_ttl + task_extra)
+		return redis.call('INCR', fence_key)
+	`)
 
-	// 解锁
-	unLockScript = `
+	// 只加任务key，用于Redis Cluster。返回fencing token，lockTaskTaken任务key已存在
+	taskLockScript = redis.NewScript(`
+		local lock_task = KEYS[1]
+		local fence_key = KEYS[2]
+		local lock_value = ARGV[1]
+		local lock_ttl = tonumber(ARGV[2])
+		if redis.call('SET', lock_task, lock_value, 'NX', 'PX', lock_ttl) then
+			return redis.call('INCR', fence_key)
+		end
+		return -2
+	`)
+
+	// 解锁：token一致时删除，返回删除的个数
+	unLockScript = redis.NewScript(`
 		local lock_key = KEYS[1]
 		local lock_value = ARGV[1]
 		if redis.call('GET', lock_key) == lock_value then
-			redis.call('DEL', lock_key)
-			return "OK"
-		else
-			return nil
+			return redis.call('DEL', lock_key)
 		end
-	`
+		return 0
+	`)
 
-	// 续期
-	renewScript = `
+	// 续期：token一致时重置过期时间，返回1成功
+	renewScript = redis.NewScript(`
 		local lock_key = KEYS[1]
 		local lock_value = ARGV[1]
 		local lock_ttl = tonumber(ARGV[2])
-		local reentrant_key = lock_key .. ':count:' .. lock_value
-		local reentrant_count = tonumber(redis.call('GET', reentrant_key) or '0')
-		
-		if reentrant_count > 0 or redis.call('GET', lock_key) == lock_value then
-			redis.call('EXPIRE', lock_key, lock_ttl)
-			redis.call('EXPIRE', reentrant_key, lock_ttl)
-			return "OK"
+		if redis.call('GET', lock_key) == lock_value then
+			return redis.call('PEXPIRE', lock_key, lock_ttl)
 		end
-		
-		return nil
-	`
+		return 0
+	`)
 )
@@ -0,0 +1,24 @@
+package scron
+
+import (
+	"time"
+)
+
+// NoopLock 不做任何互斥，每次加锁都成功
+type NoopLock struct{}
+
+func NewNoopLocker() RedisLockInter {
+	return NoopLock{}
+}
+
+// Lock 加锁
+func (NoopLock) Lock() error { return nil }
+
+// UnLock 解锁
+func (NoopLock) UnLock() error { return nil }
+
+// SpinLock 自旋锁
+func (NoopLock) SpinLock(timeout time.Duration) error { return nil }
+
+// Renew 手动续期
+func (NoopLock) Renew() error { return nil }
@@ -2,6 +2,7 @@ package scron
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"sync"
@@ -44,9 +45,10 @@ func stop(cron *Cron) chan bool {
 	return ch
 }
 
-// newWithSeconds returns a Cron with the seconds field enabled.
+// newWithSeconds returns a Cron with the seconds field enabled that does not
+// need Redis.
 func newWithSeconds() *Cron {
-	return New(WithParser(secondParser), WithChain())
+	return New(WithParser(secondParser), WithChain(), WithLockProvider(NoopLockProvider()))
 }
 
 func CronRun() {
@@ -139,3 +141,141 @@ func TestCron_FiveSpecSecond(t *testing.T) {
 		time.Sleep(1 * time.Hour)
 	})
 }
+
+func TestContextJobCancelledOnStop(t *testing.T) {
+	started := make(chan struct{})
+	cancelled := make(chan error, 1)
+
+	cron := newWithSeconds()
+	cron.AddContextJob("* * * * * ?", FuncContextJob(func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		cancelled <- ctx.Err()
+		return ctx.Err()
+	}), "ctx-stop")
+	cron.Start()
+
+	select {
+	case <-time.After(OneSecond):
+		t.Fatal("expected job to run")
+	case <-started:
+	}
+	done := cron.Stop()
+
+	select {
+	case <-time.After(time.Second):
+		t.Fatal("expected job context to be cancelled on Stop")
+	case err := <-cancelled:
+		if err != context.Canceled {
+			t.Errorf("expected %v, got %v", context.Canceled, err)
+		}
+	}
+	select {
+	case <-time.After(time.Second):
+		t.Error("expected Stop to report running jobs finished")
+	case <-done.Done():
+	}
+}
+
+func TestContextJobCancelledOnRemove(t *testing.T) {
+	started := make(chan struct{})
+	cancelled := make(chan struct{})
+
+	cron := newWithSeconds()
+	cron.AddContextJob("* * * * * ?", FuncContextJob(func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		close(cancelled)
+		return nil
+	}), "ctx-remove")
+	cron.Start()
+	defer cron.Stop()
+
+	select {
+	case <-time.After(OneSecond):
+		t.Fatal("expected job to run")
+	case <-started:
+	}
+	cron.RemoveByName("ctx-remove")
+
+	select {
+	case <-time.After(time.Second):
+		t.Error("expected job context to be cancelled on removal")
+	case <-cancelled:
+	}
+}
+
+func TestContextJobTimeout(t *testing.T) {
+	cancelled := make(chan error, 1)
+
+	cron := newWithSeconds()
+	cron.AddContextJob("* * * * * ?", FuncContextJob(func(ctx context.Context) error {
+		<-ctx.Done()
+		cancelled <- ctx.Err()
+		return ctx.Err()
+	}), "ctx-timeout", WithJobTimeout(50*time.Millisecond))
+	cron.Start()
+	defer cron.Stop()
+
+	select {
+	case <-time.After(OneSecond + 100*time.Millisecond):
+		t.Fatal("expected job to time out")
+	case err := <-cancelled:
+		if err != context.DeadlineExceeded {
+			t.Errorf("expected %v, got %v", context.DeadlineExceeded, err)
+		}
+	}
+}
+
+// Scheduled runs go through the Cron's chain, not just the bare job.
+func TestScheduledRunUsesChain(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		calls []string
+		done  = make(chan struct{}, 1)
+	)
+	record := func(call string) {
+		mu.Lock()
+		calls = append(calls, call)
+		mu.Unlock()
+	}
+	wrapper := func(j Job) Job {
+		return FuncJob(func() {
+			record("wrapper")
+			j.Run()
+		})
+	}
+
+	cron := New(WithParser(secondParser), WithChain(wrapper), WithLockProvider(NoopLockProvider()))
+	cron.AddSingleton("* * * * * ?", func() {
+		record("job")
+		done <- struct{}{}
+	}, "chained")
+	cron.Start()
+	defer cron.Stop()
+
+	select {
+	case <-time.After(OneSecond):
+		t.Fatal("expected job to run")
+	case <-done:
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(calls) < 2 || fmt.Sprint(calls[:2]) != "[wrapper job]" {
+		t.Errorf("expected the chain to wrap the job, got %v", calls)
+	}
+}
+
+func TestContextPassesThroughChain(t *testing.T) {
+	type key struct{}
+	var got interface{}
+	job := NewChain(Recover(DiscardLogger), SkipIfStillRunning(DiscardLogger), DelayIfStillRunning(DiscardLogger)).
+		Then(contextFuncJob(func(ctx context.Context) error {
+			got = ctx.Value(key{})
+			return nil
+		}))
+	runJob(context.WithValue(context.Background(), key{}, "v"), job)
+	if got != "v" {
+		t.Errorf("expected context value to reach the job, got %v", got)
+	}
+}
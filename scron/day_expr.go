@@ -0,0 +1,177 @@
+package scron
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// dayKind is the kind of a Quartz-style day expression.
+type dayKind int
+
+const (
+	lastDom        dayKind = iota // "L" or "L-n" in the day of month field
+	lastWeekdayDom                // "LW" in the day of month field
+	nearestWeekday                // "nW" in the day of month field
+	lastDow                       // "nL" in the day of week field
+	nthDow                        // "n#k" in the day of week field
+)
+
+// dayExpr is a day matched relative to the month, which bits cannot express.
+type dayExpr struct {
+	kind dayKind
+	day  int // offset for lastDom, day of month for nearestWeekday, weekday otherwise
+	nth  int // for nthDow
+}
+
+// getDayField is getField for the day of month and day of week fields, which
+// also accept the Quartz-style expressions:
+//
+//	L      last day of the month (day of month), or Saturday (day of week)
+//	L-n    n days before the last day of the month
+//	LW     last weekday (Monday to Friday) of the month
+//	nW     weekday nearest to day n, within the month
+//	nL     last weekday n of the month, e.g. 5L or FRIL for the last Friday
+//	n#k    k-th weekday n of the month, e.g. 2#2 or TUE#2 for the second Tuesday
+func getDayField(field string, r bounds, ofWeek bool) (uint64, []dayExpr, error) {
+	var (
+		bits  uint64
+		exprs []dayExpr
+	)
+	for _, expr := range strings.FieldsFunc(field, func(r rune) bool { return r == ',' }) {
+		day, ok, err := getDayExpr(expr, r, ofWeek)
+		if err != nil {
+			return 0, nil, err
+		}
+		if ok {
+			exprs = append(exprs, day)
+			continue
+		}
+		if ofWeek && strings.EqualFold(expr, "L") {
+			bits |= 1 << time.Saturday
+			continue
+		}
+		bit, err := getRange(expr, r)
+		if err != nil {
+			return 0, nil, err
+		}
+		bits |= bit
+	}
+	return bits, exprs, nil
+}
+
+// getDayExpr parses expr if it is a day expression.
+func getDayExpr(expr string, r bounds, ofWeek bool) (dayExpr, bool, error) {
+	upper := strings.ToUpper(expr)
+	if ofWeek {
+		if i := strings.Index(upper, "#"); i >= 0 {
+			weekday, err := parseIntOrName(expr[:i], r.names)
+			if err != nil {
+				return dayExpr{}, false, err
+			}
+			nth, err := mustParseInt(expr[i+1:])
+			if err != nil {
+				return dayExpr{}, false, err
+			}
+			if weekday > r.max || nth < 1 || nth > 5 {
+				return dayExpr{}, false, fmt.Errorf("invalid nth weekday: %s", expr)
+			}
+			return dayExpr{kind: nthDow, day: int(weekday), nth: int(nth)}, true, nil
+		}
+		if len(upper) > 1 && strings.HasSuffix(upper, "L") {
+			weekday, err := parseIntOrName(expr[:len(expr)-1], r.names)
+			if err != nil {
+				return dayExpr{}, false, err
+			}
+			if weekday > r.max {
+				return dayExpr{}, false, fmt.Errorf("invalid last weekday: %s", expr)
+			}
+			return dayExpr{kind: lastDow, day: int(weekday)}, true, nil
+		}
+		return dayExpr{}, false, nil
+	}
+
+	switch {
+	case upper == "L":
+		return dayExpr{kind: lastDom}, true, nil
+	case upper == "LW":
+		return dayExpr{kind: lastWeekdayDom}, true, nil
+	case strings.HasPrefix(upper, "L-"):
+		offset, err := mustParseInt(expr[2:])
+		if err != nil {
+			return dayExpr{}, false, err
+		}
+		if offset >= r.max {
+			return dayExpr{}, false, fmt.Errorf("offset from the last day (%d) above maximum (%d): %s", offset, r.max-1, expr)
+		}
+		return dayExpr{kind: lastDom, day: int(offset)}, true, nil
+	case len(upper) > 1 && strings.HasSuffix(upper, "W"):
+		day, err := mustParseInt(expr[:len(expr)-1])
+		if err != nil {
+			return dayExpr{}, false, err
+		}
+		if day < r.min || day > r.max {
+			return dayExpr{}, false, fmt.Errorf("invalid nearest weekday: %s", expr)
+		}
+		return dayExpr{kind: nearestWeekday, day: int(day)}, true, nil
+	}
+	return dayExpr{}, false, nil
+}
+
+// daysIn returns the number of days in the month of t.
+func daysIn(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// matches reports whether the day of t is the one described by d.
+func (d dayExpr) matches(t time.Time) bool {
+	last := daysIn(t)
+	switch d.kind {
+	case lastDom:
+		return t.Day() == last-d.day
+	case lastWeekdayDom:
+		day := last
+		switch time.Date(t.Year(), t.Month(), last, 0, 0, 0, 0, time.UTC).Weekday() {
+		case time.Saturday:
+			day--
+		case time.Sunday:
+			day -= 2
+		}
+		return t.Day() == day
+	case nearestWeekday:
+		if d.day > last {
+			return false
+		}
+		day := d.day
+		switch time.Date(t.Year(), t.Month(), day, 0, 0, 0, 0, time.UTC).Weekday() {
+		case time.Saturday:
+			if day == 1 {
+				day += 2
+			} else {
+				day--
+			}
+		case time.Sunday:
+			if day == last {
+				day -= 2
+			} else {
+				day++
+			}
+		}
+		return t.Day() == day
+	case lastDow:
+		return int(t.Weekday()) == d.day && t.Day()+7 > last
+	case nthDow:
+		return int(t.Weekday()) == d.day && (t.Day()-1)/7+1 == d.nth
+	}
+	return false
+}
+
+// matchDays reports whether any of the expressions of the given field matches.
+func matchDays(exprs []dayExpr, t time.Time, ofWeek bool) bool {
+	for _, d := range exprs {
+		if (d.kind >= lastDow) == ofWeek && d.matches(t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,203 @@
+package scron
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrDependencyCycle is returned by AddAfter when the entry would depend on
+// itself, directly or through its upstreams.
+var ErrDependencyCycle = errors.New("scron: dependency cycle")
+
+// UpstreamError is the result of a dependent entry that was not run because
+// one of its upstreams failed.
+type UpstreamError struct {
+	Upstream string
+	Err      error
+}
+
+func (e *UpstreamError) Error() string {
+	return fmt.Sprintf("upstream %s failed: %v", e.Upstream, e.Err)
+}
+
+func (e *UpstreamError) Unwrap() error {
+	return e.Err
+}
+
+// afterSchedule never activates: entries using it are run by their upstreams.
+type afterSchedule struct{}
+
+func (afterSchedule) Next(time.Time) time.Time {
+	return time.Time{}
+}
+
+// AddAfter adds a Job to the Cron to be run once all the upstream entries have
+// completed successfully, instead of on a schedule. Upstreams may be added
+// later; a dependency cycle is rejected with ErrDependencyCycle.
+//
+// Completions are kept in the RunStore and every round of the job is locked
+// like a scheduled run, so that with RedisRunStore the job runs once across
+// the cluster whichever nodes ran its upstreams. When an upstream fails, the
+// job is not run and its result is an UpstreamError, which in turn fails the
+// entries depending on it.
+func (c *Cron) AddAfter(upstreams []string, cmd Job, cmdName string, opts ...EntryOption) (EntryID, error) {
+	return c.Schedule(afterSchedule{}, cmd, cmdName, append([]EntryOption{withAfter(upstreams)}, opts...)...)
+}
+
+// AddContextAfter is AddAfter for a ContextJob.
+func (c *Cron) AddContextAfter(upstreams []string, cmd ContextJob, cmdName string, opts ...EntryOption) (EntryID, error) {
+	return c.AddAfter(upstreams, contextFuncJob(cmd.Run), cmdName, opts...)
+}
+
+// withAfter records the upstreams of a dependent entry.
+func withAfter(upstreams []string) EntryOption {
+	return func(e *Entry) {
+		e.After = append([]string(nil), upstreams...)
+	}
+}
+
+// checkCycle reports whether an entry named name depending on upstreams would
+// close a cycle among the given entries.
+func checkCycle(entries []Entry, name string, upstreams []string) error {
+	if len(upstreams) == 0 {
+		return nil
+	}
+	after := make(map[string][]string, len(entries))
+	for _, e := range entries {
+		after[e.Name] = e.After
+	}
+	visited := make(map[string]bool)
+	var reaches func(from string) bool
+	reaches = func(from string) bool {
+		if from == name {
+			return true
+		}
+		if visited[from] {
+			return false
+		}
+		visited[from] = true
+		for _, up := range after[from] {
+			if reaches(up) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, up := range upstreams {
+		if reaches(up) {
+			return ErrDependencyCycle
+		}
+	}
+	return nil
+}
+
+// dependsOn reports whether e runs after the named entry.
+func (e *Entry) dependsOn(name string) bool {
+	for _, up := range e.After {
+		if up == name {
+			return true
+		}
+	}
+	return false
+}
+
+// 获取key 任务名
+func doneKey(name string) string {
+	return fmt.Sprintf("done_%s", name)
+}
+
+// 获取key 任务名
+func roundKey(name string) string {
+	return fmt.Sprintf("round_%s", name)
+}
+
+// completed lets the scheduler run or fail the dependents of e. It is called
+// from the job goroutine.
+func (c *Cron) completed(e *Entry, r Result) {
+	err := c.sendControl(controlRequest{op: controlDone, name: e.Name, at: r.Scheduled, err: r.Err})
+	if err != nil && err != ErrNotRunning && err != ErrNotFound {
+		e.logger.Error(err, "dependency", "run", r.RunID)
+	}
+}
+
+// upstreamDone is called by the scheduler once the run of u scheduled at the
+// given time completed with err. A success is recorded in the RunStore for
+// the dependents of u on every node.
+func (c *Cron) upstreamDone(u *Entry, scheduled time.Time, err error, now time.Time) error {
+	if !c.running {
+		return ErrNotRunning
+	}
+	recorded := false
+	for _, d := range c.entries {
+		if !d.dependsOn(u.Name) {
+			continue
+		}
+		if err == nil && !recorded {
+			if err := c.runs.SetLastRun(doneKey(u.Name), scheduled); err != nil {
+				u.logger.Error(err, "dependency")
+				return err
+			}
+			recorded = true
+		}
+		if err != nil {
+			c.failDependent(d, scheduled, &UpstreamError{Upstream: u.Name, Err: err}, now)
+			continue
+		}
+		c.runDependent(d, now)
+	}
+	return nil
+}
+
+// failDependent records a failed result for d without running it. The result
+// is finished on its own goroutine, like a run, so that it propagates further.
+func (c *Cron) failDependent(d *Entry, scheduled time.Time, err error, now time.Time) {
+	r := Result{Entry: d.ID, Name: d.Name, RunID: newRunID(), Scheduled: scheduled, Start: now, End: now, Err: err}
+	d.logger.Info("upstream failed", "run", r.RunID, "error", err)
+	c.jobWaiter.Add(1)
+	go func() {
+		defer c.jobWaiter.Done()
+		c.finish(d, r)
+	}()
+}
+
+// runDependent runs d if all its upstreams succeeded since its last round. The
+// round is the latest of their scheduled times, so that every node reaching
+// this point for the same round contends for the same lock key.
+func (c *Cron) runDependent(d *Entry, now time.Time) {
+	if c.paused(d) {
+		c.metrics.RunSkipped(d.Name, SkipPaused)
+		return
+	}
+	last, err := c.runs.LastRun(roundKey(d.Name))
+	if err != nil {
+		d.logger.Error(err, "dependency")
+		return
+	}
+	var round time.Time
+	for _, up := range d.After {
+		done, err := c.runs.LastRun(doneKey(up))
+		if err != nil {
+			d.logger.Error(err, "dependency", "upstream", up)
+			return
+		}
+		if !done.After(last) {
+			logDebug(d.logger, "waiting", "upstream", up)
+			return
+		}
+		if done.After(round) {
+			round = done
+		}
+	}
+	round = round.In(now.Location())
+	if !c.lock(d, round) {
+		return
+	}
+	if err := c.runs.SetLastRun(roundKey(d.Name), round); err != nil {
+		d.logger.Error(err, "dependency")
+	}
+	d.logger.Info("upstreams done", "round", round)
+	c.setRunning(d)
+	c.startJob(d, []time.Time{round})
+	d.Prev = round
+}
@@ -0,0 +1,201 @@
+package scron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAddAfterRejectsCycles(t *testing.T) {
+	cron := New(WithParser(secondParser), WithLogger(DiscardLogger), WithLockProvider(NoopLockProvider()))
+	noop := FuncJob(func() {})
+	if _, err := cron.AddAfter([]string{"self"}, noop, "self"); err != ErrDependencyCycle {
+		t.Errorf("expected a cycle, got %v", err)
+	}
+	if _, err := cron.AddAfter([]string{"c"}, noop, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cron.AddAfter([]string{"a"}, noop, "b"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cron.AddAfter([]string{"x", "b"}, noop, "c"); err != ErrDependencyCycle {
+		t.Errorf("expected a cycle, got %v", err)
+	}
+	if _, err := cron.AddAfter([]string{"a", "b"}, noop, "d"); err != nil {
+		t.Errorf("expected a diamond to be accepted, got %v", err)
+	}
+}
+
+func TestAddAfterConcurrentCycle(t *testing.T) {
+	cron := New(WithParser(secondParser), WithLogger(DiscardLogger), WithLockProvider(NoopLockProvider()))
+	cron.Start()
+	defer cron.Stop()
+	noop := FuncJob(func() {})
+
+	for i := 0; i < 20; i++ {
+		a, b := fmt.Sprintf("a%d", i), fmt.Sprintf("b%d", i)
+		var (
+			wg    sync.WaitGroup
+			added int32
+		)
+		for _, pair := range [][2]string{{a, b}, {b, a}} {
+			wg.Add(1)
+			go func(name, upstream string) {
+				defer wg.Done()
+				if _, err := cron.AddAfter([]string{upstream}, noop, name); err == nil {
+					atomic.AddInt32(&added, 1)
+				}
+			}(pair[0], pair[1])
+		}
+		wg.Wait()
+		if added != 1 {
+			t.Fatalf("expected exactly one of two entries depending on each other added, got %d", added)
+		}
+	}
+}
+
+// dag records the jobs run and the results of a pipeline a, b -> c -> d.
+type dag struct {
+	mu      sync.Mutex
+	ran     []string
+	results map[string]Result
+}
+
+func (d *dag) job(name string, err error) Job {
+	return contextFuncJob(func(context.Context) error {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		d.ran = append(d.ran, name)
+		return err
+	})
+}
+
+func (d *dag) hook(r Result) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.results[r.Name] = r
+}
+
+func (d *dag) result(name string) (Result, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	r, ok := d.results[name]
+	return r, ok
+}
+
+func (d *dag) count(name string) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	n := 0
+	for _, ran := range d.ran {
+		if ran == name {
+			n++
+		}
+	}
+	return n
+}
+
+func newDAG(t *testing.T, d *dag, locks LockProvider, runs RunStore, errA error) *Cron {
+	cron := New(WithParser(secondParser), WithChain(), WithLogger(DiscardLogger),
+		WithLockProvider(locks), WithRunStore(runs), WithResultHooks(d.hook))
+	cron.AddJob("@yearly", d.job("a", errA), "a")
+	cron.AddJob("@yearly", d.job("b", nil), "b")
+	if _, err := cron.AddAfter([]string{"a", "b"}, d.job("c", nil), "c"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cron.AddAfter([]string{"c"}, d.job("d", nil), "d"); err != nil {
+		t.Fatal(err)
+	}
+	return cron
+}
+
+func TestAddAfterRunsWhenUpstreamsSucceed(t *testing.T) {
+	d := &dag{results: make(map[string]Result)}
+	cron := newDAG(t, d, LocalLockProvider(), MemoryRunStore(), nil)
+	cron.Start()
+	defer cron.Stop()
+
+	if err := cron.Trigger("a"); err != nil {
+		t.Fatal(err)
+	}
+	if !eventually(func() bool { _, ok := d.result("a"); return ok }) {
+		t.Fatal("expected a to run")
+	}
+	time.Sleep(50 * time.Millisecond)
+	if d.count("c") != 0 {
+		t.Fatal("expected c to wait for b")
+	}
+
+	if err := cron.Trigger("b"); err != nil {
+		t.Fatal(err)
+	}
+	if !eventually(func() bool { _, ok := d.result("d"); return ok }) {
+		t.Fatalf("expected d to run, ran %v", d.ran)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if got := d.ran; len(got) != 4 || got[2] != "c" || got[3] != "d" {
+		t.Errorf("expected a, b, c, d to run in order, got %v", got)
+	}
+	if c := d.results["c"]; c.Err != nil || c.Scheduled != d.results["b"].Scheduled {
+		t.Errorf("expected c to run in the round of b, got %+v", c)
+	}
+}
+
+func TestAddAfterPropagatesFailure(t *testing.T) {
+	d := &dag{results: make(map[string]Result)}
+	boom := errors.New("boom")
+	cron := newDAG(t, d, LocalLockProvider(), MemoryRunStore(), boom)
+	cron.Start()
+	defer cron.Stop()
+
+	cron.Trigger("b")
+	if err := cron.Trigger("a"); err != nil {
+		t.Fatal(err)
+	}
+	if !eventually(func() bool { _, ok := d.result("d"); return ok }) {
+		t.Fatal("expected a result for d")
+	}
+	for _, name := range []string{"c", "d"} {
+		r, _ := d.result(name)
+		var upstream *UpstreamError
+		if !errors.As(r.Err, &upstream) || !errors.Is(r.Err, boom) {
+			t.Errorf("expected %s to fail with the upstream error, got %v", name, r.Err)
+		}
+		if d.count(name) != 0 {
+			t.Errorf("expected %s not to run", name)
+		}
+	}
+}
+
+func TestAddAfterRunsOnceInCluster(t *testing.T) {
+	d := &dag{results: make(map[string]Result)}
+	locks, runs := LocalLockProvider(), MemoryRunStore()
+	node1 := newDAG(t, d, locks, runs, nil)
+	node2 := newDAG(t, d, locks, runs, nil)
+	node1.Start()
+	defer node1.Stop()
+	node2.Start()
+	defer node2.Stop()
+
+	node1.Trigger("a")
+	if !eventually(func() bool { _, ok := d.result("a"); return ok }) {
+		t.Fatal("expected a to run")
+	}
+	node2.Trigger("b")
+	if !eventually(func() bool { _, ok := d.result("d"); return ok }) {
+		t.Fatal("expected d to run")
+	}
+
+	// Node 1 learning that b completed too contends for the same round.
+	b, _ := d.result("b")
+	node1.sendControl(controlRequest{op: controlDone, name: "b", at: b.Scheduled})
+	time.Sleep(50 * time.Millisecond)
+	if d.count("c") != 1 || d.count("d") != 1 {
+		t.Errorf("expected c and d to run once, ran %v", d.ran)
+	}
+}
@@ -0,0 +1,552 @@
+package scron
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Language selects the language of Describe.
+type Language int
+
+const (
+	English Language = iota
+	Chinese
+)
+
+// ParseLanguage returns the language of a tag like "en" or "zh-CN", English
+// if unknown.
+func ParseLanguage(tag string) Language {
+	if strings.HasPrefix(strings.ToLower(tag), "zh") {
+		return Chinese
+	}
+	return English
+}
+
+// Describe returns a human readable description of the schedule, e.g.
+// "Every 15 minutes, between 09:00 and 18:59, Monday through Friday" for
+// "0 */15 9-18 * * 1-5", in English unless another language is given. It
+// returns an empty string for schedules of other packages.
+func Describe(schedule Schedule, lang ...Language) string {
+	l := English
+	if len(lang) > 0 {
+		l = lang[0]
+	}
+	switch s := schedule.(type) {
+	case *SpecSchedule:
+		return describeSpec(s, l)
+	case ConstantDelaySchedule:
+		return everyText(s.Delay, l)
+	case FixedRateSchedule:
+		if l == Chinese {
+			return everyText(s.Interval, l) + "，" + s.Start.Format("2006-01-02 15:04:05.000") + "起"
+		}
+		return everyText(s.Interval, l) + ", from " + s.Start.Format("2006-01-02 15:04:05.000")
+	case FixedDelaySchedule:
+		if l == Chinese {
+			return "上次执行完成" + durationText(s.Delay, l) + "后"
+		}
+		return durationText(s.Delay, l) + " after the previous run completes"
+	case OnceSchedule:
+		if l == Chinese {
+			return "仅在" + s.At.Format("2006-01-02 15:04:05") + "执行一次"
+		}
+		return "Once at " + s.At.Format("2006-01-02 15:04:05")
+	case BoundedSchedule:
+		desc := Describe(s.Schedule, l)
+		if !s.Start.IsZero() {
+			if l == Chinese {
+				desc += "，" + s.Start.Format("2006-01-02 15:04:05") + "起"
+			} else {
+				desc += ", from " + s.Start.Format("2006-01-02 15:04:05")
+			}
+		}
+		if !s.End.IsZero() {
+			if l == Chinese {
+				desc += "，至" + s.End.Format("2006-01-02 15:04:05") + "止"
+			} else {
+				desc += ", until " + s.End.Format("2006-01-02 15:04:05")
+			}
+		}
+		return desc
+	case JitterSchedule:
+		if l == Chinese {
+			return Describe(s.Schedule, l) + "，延迟" + durationText(s.Offset, l)
+		}
+		return Describe(s.Schedule, l) + ", delayed by " + durationText(s.Offset, l)
+	case afterSchedule:
+		if l == Chinese {
+			return "上游任务成功后执行"
+		}
+		return "After its upstream entries succeed"
+	}
+	return ""
+}
+
+// NextN returns up to n activations of the schedule after from, fewer if the
+// schedule is exhausted.
+func NextN(schedule Schedule, from time.Time, n int) []time.Time {
+	var next []time.Time
+	for t := schedule.Next(from); len(next) < n && !t.IsZero(); t = schedule.Next(t) {
+		next = append(next, t)
+	}
+	return next
+}
+
+// Upcoming returns up to n activations of the entry from its next one.
+func (e Entry) Upcoming(n int) []time.Time {
+	if e.Next.IsZero() || n <= 0 {
+		return nil
+	}
+	return append([]time.Time{e.Next}, NextN(e.Schedule, e.Next, n-1)...)
+}
+
+// everyText returns "Every 90 seconds", or "Every hour" for a single unit.
+func everyText(d time.Duration, l Language) string {
+	text := durationText(d, l)
+	if l == Chinese {
+		if len(text) > 1 && text[0] == '1' && !strings.ContainsRune("0123456789.", rune(text[1])) {
+			text = text[1:]
+		}
+		return "每" + text
+	}
+	return "Every " + strings.TrimPrefix(text, "1 ")
+}
+
+// durationText returns d in the largest unit that divides it, e.g. "90 seconds".
+func durationText(d time.Duration, l Language) string {
+	units := []struct {
+		d      time.Duration
+		en, zh string
+	}{
+		{time.Hour, "hour", "小时"},
+		{time.Minute, "minute", "分钟"},
+		{time.Second, "second", "秒"},
+		{time.Millisecond, "millisecond", "毫秒"},
+	}
+	for _, u := range units {
+		if d < u.d || d%u.d != 0 {
+			continue
+		}
+		n := int64(d / u.d)
+		switch {
+		case l == Chinese:
+			return strconv.FormatInt(n, 10) + u.zh
+		case n == 1:
+			return "1 " + u.en
+		default:
+			return strconv.FormatInt(n, 10) + " " + u.en + "s"
+		}
+	}
+	return d.String()
+}
+
+// fieldDesc is the shape of a field of a SpecSchedule: every value, every
+// step values from start, or a list of spans.
+type fieldDesc struct {
+	every       bool
+	step, start int
+	spans       [][2]int
+}
+
+func (f fieldDesc) single() (int, bool) {
+	if len(f.spans) == 1 && f.spans[0][0] == f.spans[0][1] {
+		return f.spans[0][0], true
+	}
+	return 0, false
+}
+
+func (f fieldDesc) singles() bool {
+	for _, s := range f.spans {
+		if s[0] != s[1] {
+			return false
+		}
+	}
+	return len(f.spans) > 0
+}
+
+func (f fieldDesc) restricted() bool {
+	return !f.every && (f.step > 0 || len(f.spans) > 0)
+}
+
+// describeField returns the shape of the field's bits, finding progressions
+// unless it is the day of week, where they read worse than lists.
+func describeField(b uint64, r bounds) fieldDesc {
+	if b&starBit > 0 || b&getBits(r.min, r.max, 1) == getBits(r.min, r.max, 1) {
+		return fieldDesc{every: true}
+	}
+	var vals []int
+	for b &= getBits(r.min, r.max, 1); b != 0; b &= b - 1 {
+		vals = append(vals, bits.TrailingZeros64(b))
+	}
+	if len(vals) >= 2 && r.max != dow.max {
+		step := vals[1] - vals[0]
+		progression := step > 1 && vals[0]-int(r.min) < step && vals[len(vals)-1]+step > int(r.max)
+		for i := 2; progression && i < len(vals); i++ {
+			progression = vals[i]-vals[i-1] == step
+		}
+		if progression {
+			return fieldDesc{step: step, start: vals[0]}
+		}
+	}
+	var f fieldDesc
+	for _, v := range vals {
+		if n := len(f.spans); n > 0 && f.spans[n-1][1] == v-1 {
+			f.spans[n-1][1] = v
+			continue
+		}
+		f.spans = append(f.spans, [2]int{v, v})
+	}
+	return f
+}
+
+var (
+	weekdaysEn = []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}
+	weekdaysZh = []string{"周日", "周一", "周二", "周三", "周四", "周五", "周六"}
+	ordinalsEn = []string{"", "first", "second", "third", "fourth", "fifth"}
+	ordinalsZh = []string{"", "一", "二", "三", "四", "五"}
+)
+
+// list joins the items as "a, b and c" or "a、b和c".
+func list(items []string, l Language) string {
+	if len(items) < 2 {
+		return strings.Join(items, "")
+	}
+	if l == Chinese {
+		return strings.Join(items[:len(items)-1], "、") + "和" + items[len(items)-1]
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
+
+// spans formats the spans of f with name, ranges joined by through.
+func spans(f fieldDesc, through string, name func(int) string) []string {
+	var items []string
+	for _, s := range f.spans {
+		if s[0] == s[1] {
+			items = append(items, name(s[0]))
+		} else {
+			items = append(items, name(s[0])+through+name(s[1]))
+		}
+	}
+	return items
+}
+
+func describeSpec(s *SpecSchedule, l Language) string {
+	var (
+		second = describeField(s.Second, seconds)
+		minute = describeField(s.Minute, minutes)
+		hour   = describeField(s.Hour, hours)
+		day    = describeField(s.Dom, dom)
+		month  = describeField(s.Month, months)
+		week   = describeField(s.Dow, dow)
+	)
+	var domExprs, dowExprs []dayExpr
+	for _, d := range s.days {
+		if d.kind >= lastDow {
+			dowExprs = append(dowExprs, d)
+		} else {
+			domExprs = append(domExprs, d)
+		}
+	}
+	if len(domExprs) > 0 && day.every {
+		day = fieldDesc{}
+	}
+	if len(dowExprs) > 0 && week.every {
+		week = fieldDesc{}
+	}
+
+	var (
+		timeText, at = describeTime(second, minute, hour, l)
+		dates        []string
+		days         []string
+	)
+	if day.restricted() || len(domExprs) > 0 {
+		days = append(days, describeDom(day, domExprs, l))
+	}
+	if week.restricted() || len(dowExprs) > 0 {
+		days = append(days, describeDow(week, dowExprs, l))
+	}
+	if len(days) > 0 {
+		sep := " or "
+		if l == Chinese {
+			sep = "或"
+		}
+		dates = append(dates, strings.Join(days, sep))
+	}
+	if month.restricted() {
+		dates = append(dates, describeMonth(month, l))
+	}
+	if s.years != nil {
+		dates = append(dates, describeYears(s.years, l))
+	}
+
+	var desc string
+	if l == Chinese {
+		desc = strings.Join(append(reverse(dates), timeText...), "，")
+		if at && len(dates) == 0 {
+			desc = "每天" + desc
+		}
+	} else {
+		desc = strings.Join(append(timeText, dates...), ", ")
+		desc = strings.ToUpper(desc[:1]) + desc[1:]
+	}
+	if s.Location != nil && s.Location != time.Local {
+		if l == Chinese {
+			desc += "（" + s.Location.String() + "）"
+		} else {
+			desc += " (" + s.Location.String() + ")"
+		}
+	}
+	return desc
+}
+
+func reverse(items []string) []string {
+	reversed := make([]string, len(items))
+	for i, item := range items {
+		reversed[len(items)-1-i] = item
+	}
+	return reversed
+}
+
+// describeTime describes the time of day, e.g. "every 15 minutes" and
+// "between 09:00 and 18:59", reporting whether it is a list of times such as
+// "at 09:30".
+func describeTime(second, minute, hour fieldDesc, l Language) ([]string, bool) {
+	zh := l == Chinese
+	sec, secSingle := second.single()
+	min, minSingle := minute.single()
+	if secSingle && minSingle && hour.singles() {
+		var times []string
+		for _, s := range hour.spans {
+			t := fmt.Sprintf("%02d:%02d", s[0], min)
+			if sec != 0 {
+				t += fmt.Sprintf(":%02d", sec)
+			}
+			times = append(times, t)
+		}
+		if zh {
+			return []string{list(times, l)}, true
+		}
+		return []string{"at " + list(times, l)}, true
+	}
+
+	var parts []string
+	switch {
+	case second.every:
+		parts = append(parts, pick(zh, "every second", "每秒"))
+	case second.step > 0:
+		parts = append(parts, stepText(second, "second", "秒", "", l))
+	case secSingle && sec == 0:
+	default:
+		items := spans(second, pick(zh, " through ", "至"), strconv.Itoa)
+		if zh {
+			parts = append(parts, "第"+list(items, l)+"秒")
+		} else {
+			parts = append(parts, "at second "+list(items, l))
+		}
+	}
+
+	switch {
+	case minute.every:
+		if !second.every && second.step == 0 {
+			parts = append(parts, pick(zh, "every minute", "每分钟"))
+		}
+	case minute.step > 0:
+		parts = append(parts, stepText(minute, "minute", "分钟", "", l))
+	case minSingle && min == 0 && hour.every:
+		parts = append(parts, pick(zh, "every hour", "每小时"))
+	case minSingle && min == 0 && hour.step > 0:
+		// On the hour, said by the hour step.
+	default:
+		items := spans(minute, pick(zh, " through ", "至"), strconv.Itoa)
+		if zh {
+			parts = append(parts, "每小时第"+list(items, l)+"分钟")
+		} else if minSingle {
+			parts = append(parts, "at "+items[0]+" minutes past the hour")
+		} else {
+			parts = append(parts, "at minutes "+list(items, l)+" past the hour")
+		}
+	}
+
+	switch {
+	case hour.every:
+	case hour.step > 0:
+		parts = append(parts, stepText(hour, "hour", "小时", ":00", l))
+	case len(hour.spans) == 1:
+		s := hour.spans[0]
+		if zh {
+			parts = append(parts, fmt.Sprintf("%02d:00至%02d:59之间", s[0], s[1]))
+		} else {
+			parts = append(parts, fmt.Sprintf("between %02d:00 and %02d:59", s[0], s[1]))
+		}
+	default:
+		items := spans(hour, pick(zh, " through ", "至"), strconv.Itoa)
+		if zh {
+			parts = append(parts, list(items, l)+"点")
+		} else {
+			parts = append(parts, "during hours "+list(items, l))
+		}
+	}
+
+	if zh {
+		// Largest unit first.
+		return reverse(parts), false
+	}
+	return parts, false
+}
+
+// stepText describes a progression, e.g. "every 15 minutes, starting at
+// minute 5".
+func stepText(f fieldDesc, en, zh, clock string, l Language) string {
+	if l == Chinese {
+		text := "每" + strconv.Itoa(f.step) + zh
+		if f.start > 0 {
+			if clock != "" {
+				text += fmt.Sprintf("，从%02d%s开始", f.start, clock)
+			} else {
+				text += fmt.Sprintf("，从第%d%s开始", f.start, zh)
+			}
+		}
+		return text
+	}
+	text := "every " + strconv.Itoa(f.step) + " " + en + "s"
+	if f.start > 0 {
+		if clock != "" {
+			text += fmt.Sprintf(", starting at %02d%s", f.start, clock)
+		} else {
+			text += fmt.Sprintf(", starting at %s %d", en, f.start)
+		}
+	}
+	return text
+}
+
+func describeDom(day fieldDesc, exprs []dayExpr, l Language) string {
+	zh := l == Chinese
+	var items []string
+	switch {
+	case day.step > 0:
+		if zh {
+			items = append(items, fmt.Sprintf("每月从%d日起每%d天", day.start, day.step))
+		} else {
+			items = append(items, fmt.Sprintf("every %d days of the month, starting on day %d", day.step, day.start))
+		}
+	case len(day.spans) > 0:
+		if zh {
+			days := spans(day, "至", func(d int) string { return strconv.Itoa(d) + "日" })
+			items = append(items, "每月"+list(days, l))
+		} else {
+			days := spans(day, " through ", strconv.Itoa)
+			items = append(items, "on day "+list(days, l)+" of the month")
+		}
+	}
+	for _, d := range exprs {
+		switch {
+		case d.kind == lastDom && d.day == 0:
+			items = append(items, pick(zh, "on the last day of the month", "每月最后一天"))
+		case d.kind == lastDom && zh:
+			items = append(items, fmt.Sprintf("每月倒数第%d天", d.day+1))
+		case d.kind == lastDom:
+			items = append(items, fmt.Sprintf("%d days before the last day of the month", d.day))
+		case d.kind == lastWeekdayDom:
+			items = append(items, pick(zh, "on the last weekday of the month", "每月最后一个工作日"))
+		case d.kind == nearestWeekday && zh:
+			items = append(items, fmt.Sprintf("每月离%d日最近的工作日", d.day))
+		case d.kind == nearestWeekday:
+			items = append(items, fmt.Sprintf("on the weekday nearest day %d of the month", d.day))
+		}
+	}
+	return list(items, l)
+}
+
+func describeDow(week fieldDesc, exprs []dayExpr, l Language) string {
+	zh := l == Chinese
+	names := weekdaysEn
+	if zh {
+		names = weekdaysZh
+	}
+	name := func(d int) string { return names[d] }
+	var items []string
+	switch {
+	case len(week.spans) == 1 && week.spans[0][0] != week.spans[0][1]:
+		items = append(items, spans(week, pick(zh, " through ", "至"), name)...)
+	case len(week.spans) > 0:
+		days := spans(week, pick(zh, " through ", "至"), name)
+		if zh {
+			items = append(items, "每"+list(days, l))
+		} else {
+			items = append(items, "on "+list(days, l))
+		}
+	}
+	for _, d := range exprs {
+		switch {
+		case d.kind == lastDow && zh:
+			items = append(items, "每月最后一个"+names[d.day])
+		case d.kind == lastDow:
+			items = append(items, "on the last "+names[d.day]+" of the month")
+		case d.kind == nthDow && zh:
+			items = append(items, "每月第"+ordinalsZh[d.nth]+"个"+names[d.day])
+		case d.kind == nthDow:
+			items = append(items, "on the "+ordinalsEn[d.nth]+" "+names[d.day]+" of the month")
+		}
+	}
+	return list(items, l)
+}
+
+func describeMonth(month fieldDesc, l Language) string {
+	zh := l == Chinese
+	name := func(m int) string {
+		if zh {
+			return strconv.Itoa(m) + "月"
+		}
+		return time.Month(m).String()
+	}
+	switch {
+	case month.step > 0 && zh:
+		return fmt.Sprintf("从%s起每%d个月", name(month.start), month.step)
+	case month.step > 0:
+		return fmt.Sprintf("every %d months, starting in %s", month.step, name(month.start))
+	case len(month.spans) == 1 && month.spans[0][0] != month.spans[0][1]:
+		return spans(month, pick(zh, " through ", "至"), name)[0]
+	case zh:
+		return "仅在" + list(spans(month, "至", name), l)
+	}
+	return "only in " + list(spans(month, " through ", name), l)
+}
+
+func describeYears(ranges []yearRange, l Language) string {
+	zh := l == Chinese
+	var items []string
+	for _, r := range ranges {
+		item := strconv.Itoa(int(r.start))
+		if r.end != r.start {
+			item += pick(zh, " through ", "至") + strconv.Itoa(int(r.end))
+			if zh {
+				item = strconv.Itoa(int(r.start)) + "年至" + strconv.Itoa(int(r.end))
+			}
+		}
+		if zh {
+			item += "年"
+		}
+		if r.step > 1 {
+			if zh {
+				item += fmt.Sprintf("每%d年", r.step)
+			} else {
+				item += fmt.Sprintf(" every %d years", r.step)
+			}
+		}
+		items = append(items, item)
+	}
+	if zh {
+		return "仅在" + list(items, l)
+	}
+	return "only in " + list(items, l)
+}
+
+func pick(zh bool, en, chinese string) string {
+	if zh {
+		return chinese
+	}
+	return en
+}
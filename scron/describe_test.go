@@ -0,0 +1,99 @@
+package scron
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		spec   string
+		en, zh string
+	}{
+		{"0 */15 9-18 * * 1-5", "Every 15 minutes, between 09:00 and 18:59, Monday through Friday", "周一至周五，09:00至18:59之间，每15分钟"},
+		{"0 30 9 * * *", "At 09:30", "每天09:30"},
+		{"0 0 9,18 * * *", "At 09:00 and 18:00", "每天09:00和18:00"},
+		{"15 10 8 * 6-8 *", "At 08:10:15, June through August", "6月至8月，08:10:15"},
+		{"*/10 * * * * *", "Every 10 seconds", "每10秒"},
+		{"0 * * * * *", "Every minute", "每分钟"},
+		{"0 5 * * * *", "At 5 minutes past the hour", "每小时第5分钟"},
+		{"0 0,30 * * * *", "Every 30 minutes", "每30分钟"},
+		{"0 0 */2 * * *", "Every 2 hours", "每2小时"},
+		{"5-10 3 * * * *", "At second 5 through 10, at 3 minutes past the hour", "每小时第3分钟，第5至10秒"},
+		{"0 0 10 1,15 * MON", "At 10:00, on day 1 and 15 of the month or on Monday", "每月1日和15日或每周一，10:00"},
+		{"0 0 9 * * 1,3,5", "At 09:00, on Monday, Wednesday and Friday", "每周一、周三和周五，09:00"},
+		{"0 0 18 LW * ?", "At 18:00, on the last weekday of the month", "每月最后一个工作日，18:00"},
+		{"0 0 10 ? * TUE#2", "At 10:00, on the second Tuesday of the month", "每月第二个周二，10:00"},
+		{"0 0 10 ? * 5L", "At 10:00, on the last Friday of the month", "每月最后一个周五，10:00"},
+		{"@hourly", "Every hour", "每小时"},
+		{"@weekly", "At 00:00, on Sunday", "每周日，00:00"},
+		{"@yearly", "At 00:00, on day 1 of the month, only in January", "仅在1月，每月1日，00:00"},
+		{"@every 90s", "Every 90 seconds", "每90秒"},
+		{"CRON_TZ=Asia/Shanghai 0 0 9 * * *", "At 09:00 (Asia/Shanghai)", "每天09:00（Asia/Shanghai）"},
+	}
+	for _, test := range tests {
+		s, err := secondParser.Parse(test.spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual := Describe(s); actual != test.en {
+			t.Errorf("%s: expected %q, got %q", test.spec, test.en, actual)
+		}
+		if actual := Describe(s, Chinese); actual != test.zh {
+			t.Errorf("%s: expected %q, got %q", test.spec, test.zh, actual)
+		}
+	}
+
+	s, _ := ParseStandard("0 0 0 1 1 * 2030")
+	if actual := Describe(s); actual != "At 00:00, on day 1 of the month, only in January, only in 2030" {
+		t.Errorf("unexpected description of a year %q", actual)
+	}
+	if actual := Describe(OnceAt(getTime("Mon Jul 9 15:00 2012")), Chinese); actual != "仅在2012-07-09 15:00:00执行一次" {
+		t.Errorf("unexpected description of OnceAt %q", actual)
+	}
+	if actual := Describe(JitterSchedule{Every(time.Hour), 90 * time.Second}); actual != "Every hour, delayed by 90 seconds" {
+		t.Errorf("unexpected description of a jitter %q", actual)
+	}
+	if actual := Describe(FixedDelay(time.Second)); actual != "1 second after the previous run completes" {
+		t.Errorf("unexpected description of a fixed delay %q", actual)
+	}
+	if actual := Describe(FixedRate(15*time.Second, time.Time{}), Chinese); !strings.HasPrefix(actual, "每15秒，") {
+		t.Errorf("unexpected description of a fixed rate %q", actual)
+	}
+	if actual := Describe(FixedRate(250*time.Millisecond, time.Time{})); !strings.HasPrefix(actual, "Every 250 milliseconds, ") {
+		t.Errorf("unexpected description of a fixed rate %q", actual)
+	}
+}
+
+func TestNextN(t *testing.T) {
+	s, _ := ParseStandard("0 9 * * 1-5")
+	actual := NextN(s, getTime("Fri Jul 6 10:00 2012"), 3)
+	expected := []time.Time{
+		getTime("Mon Jul 9 09:00 2012"),
+		getTime("Tue Jul 10 09:00 2012"),
+		getTime("Wed Jul 11 09:00 2012"),
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+	if once := NextN(OnceAt(expected[0]), expected[0].Add(-time.Hour), 3); len(once) != 1 {
+		t.Errorf("expected a single activation, got %v", once)
+	}
+}
+
+func TestAdminDescribesEntries(t *testing.T) {
+	cron := New(WithLogger(DiscardLogger), WithLockProvider(NoopLockProvider()))
+	cron.AddSingleton("0 9 * * *", func() {}, "daily")
+	cron.Start()
+	defer cron.Stop()
+
+	var entries []EntryView
+	adminRequest(t, AdminHandler(cron), http.MethodGet, "/entries?lang=zh-CN&next=3", &entries)
+	if len(entries) != 1 || entries[0].Description != "每天09:00" || len(entries[0].Upcoming) != 3 ||
+		!entries[0].Upcoming[0].Equal(entries[0].Next) || !entries[0].Upcoming[1].After(entries[0].Next) {
+		t.Errorf("unexpected entries %+v", entries)
+	}
+}
@@ -0,0 +1,140 @@
+package scron
+
+import "time"
+
+// DSTMode is how a SpecSchedule treats activations at wall clock times that a
+// daylight saving transition skips (a gap) or repeats (an overlap).
+type DSTMode int
+
+const (
+	// DSTDefault skips activations in gaps and runs those in overlaps twice,
+	// as a plain SpecSchedule does.
+	DSTDefault DSTMode = iota
+
+	// DSTSkip does not run activations in the gap or overlap.
+	DSTSkip
+
+	// DSTRunOnce runs activations in a gap once, when the gap ends, and
+	// those in an overlap once, at their first occurrence.
+	DSTRunOnce
+
+	// DSTRunTwice runs activations in an overlap at both occurrences.
+	DSTRunTwice
+)
+
+// DSTPolicy selects the DSTMode for gaps and overlaps separately, e.g.
+//
+//	DSTPolicy{Gap: DSTRunOnce, Overlap: DSTRunOnce}
+//
+// runs a daily job at 02:30 every day in zones moving clocks at 02:00.
+// Gap is DSTSkip or DSTRunOnce; Overlap is DSTRunTwice, DSTRunOnce or
+// DSTSkip.
+type DSTPolicy struct {
+	Gap     DSTMode
+	Overlap DSTMode
+}
+
+// localize applies the location and DST policy of the entry to a
+// SpecSchedule. Other schedules are returned as is.
+func (e *Entry) localize(schedule Schedule) Schedule {
+	spec, ok := schedule.(*SpecSchedule)
+	if !ok || (e.location == nil && e.dst == DSTPolicy{}) {
+		return schedule
+	}
+	local := *spec
+	if e.location != nil {
+		local.Location = e.location
+	}
+	if e.dst != (DSTPolicy{}) {
+		local.DST = e.dst
+	}
+	return &local
+}
+
+// nextDST is next with the DST policy applied.
+func (s *SpecSchedule) nextDST(t time.Time) time.Time {
+	loc := s.Location
+	if loc == time.Local {
+		loc = t.Location()
+	}
+	for {
+		next := s.next(t)
+		if next.IsZero() {
+			return next
+		}
+		if s.DST.Gap == DSTRunOnce {
+			if end := s.gapActivation(t, next.In(loc)); !end.IsZero() {
+				return end.In(next.Location())
+			}
+		}
+		if skipOverlap(s.DST.Overlap, next.In(loc)) {
+			t = next
+			continue
+		}
+		return next
+	}
+}
+
+// gapActivation returns the end of the earliest DST gap between from and
+// next that an activation fell into, or the zero time.
+func (s *SpecSchedule) gapActivation(from, next time.Time) time.Time {
+	var found time.Time
+	for at := next; ; {
+		start, _ := at.ZoneBounds()
+		if start.IsZero() || !start.After(from) {
+			return found
+		}
+		before := start.Add(-time.Second)
+		_, offBefore := before.Zone()
+		_, offAfter := start.Zone()
+		if gap := time.Duration(offAfter-offBefore) * time.Second; gap > 0 {
+			// Evaluate the schedule on the wall clock as if the transition
+			// had not happened, to find activations in the skipped hour.
+			fixed := *s
+			fixed.Location = time.FixedZone("", offBefore)
+			fixed.DST = DSTPolicy{}
+			if m := fixed.next(before); !m.IsZero() && m.Before(start.Add(gap)) {
+				found = start
+			}
+		}
+		at = before
+	}
+}
+
+// skipOverlap reports whether the activation at t is skipped by the overlap
+// mode because its wall clock time occurs twice.
+func skipOverlap(mode DSTMode, t time.Time) bool {
+	switch mode {
+	case DSTRunOnce:
+		return secondOccurrence(t)
+	case DSTSkip:
+		return secondOccurrence(t) || firstOccurrence(t)
+	}
+	return false
+}
+
+// secondOccurrence reports whether the wall clock time of t already occurred
+// before the clocks were set back.
+func secondOccurrence(t time.Time) bool {
+	start, _ := t.ZoneBounds()
+	if start.IsZero() {
+		return false
+	}
+	_, off := t.Zone()
+	_, offBefore := start.Add(-time.Second).Zone()
+	back := time.Duration(offBefore-off) * time.Second
+	return back > 0 && t.Sub(start) < back
+}
+
+// firstOccurrence reports whether the wall clock time of t occurs again once
+// the clocks are set back.
+func firstOccurrence(t time.Time) bool {
+	_, end := t.ZoneBounds()
+	if end.IsZero() {
+		return false
+	}
+	_, off := t.Zone()
+	_, offAfter := end.Zone()
+	back := time.Duration(off-offAfter) * time.Second
+	return back > 0 && end.Sub(t) <= back
+}
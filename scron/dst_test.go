@@ -0,0 +1,158 @@
+package scron
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSpecScheduleDST(t *testing.T) {
+	var (
+		gapOnce     = DSTPolicy{Gap: DSTRunOnce}
+		overlapOnce = DSTPolicy{Overlap: DSTRunOnce}
+		overlapSkip = DSTPolicy{Overlap: DSTSkip}
+		once        = DSTPolicy{Gap: DSTRunOnce, Overlap: DSTRunOnce}
+	)
+	tests := []struct {
+		zone   string
+		spec   string
+		from   string
+		policy DSTPolicy
+		want   []string
+	}{
+		// America/New_York: 2023-03-12 02:00 -> 03:00, 2023-11-05 02:00 -> 01:00.
+		{"America/New_York", "0 30 2 * * *", "2023-03-11 00:00", DSTPolicy{},
+			[]string{"2023-03-11 02:30 -0500", "2023-03-13 02:30 -0400", "2023-03-14 02:30 -0400"}},
+		{"America/New_York", "0 30 2 * * *", "2023-03-11 00:00", gapOnce,
+			[]string{"2023-03-11 02:30 -0500", "2023-03-12 03:00 -0400", "2023-03-13 02:30 -0400"}},
+		{"America/New_York", "0 30 1 * * *", "2023-11-04 12:00", DSTPolicy{},
+			[]string{"2023-11-05 01:30 -0400", "2023-11-05 01:30 -0500", "2023-11-06 01:30 -0500"}},
+		{"America/New_York", "0 30 1 * * *", "2023-11-04 12:00", DSTPolicy{Overlap: DSTRunTwice},
+			[]string{"2023-11-05 01:30 -0400", "2023-11-05 01:30 -0500", "2023-11-06 01:30 -0500"}},
+		{"America/New_York", "0 30 1 * * *", "2023-11-04 12:00", overlapOnce,
+			[]string{"2023-11-05 01:30 -0400", "2023-11-06 01:30 -0500", "2023-11-07 01:30 -0500"}},
+		{"America/New_York", "0 30 1 * * *", "2023-11-04 12:00", overlapSkip,
+			[]string{"2023-11-06 01:30 -0500", "2023-11-07 01:30 -0500"}},
+		{"America/New_York", "0 0 * * * *", "2023-11-05 00:00", DSTPolicy{},
+			[]string{"2023-11-05 01:00 -0400", "2023-11-05 01:00 -0500", "2023-11-05 02:00 -0500"}},
+		{"America/New_York", "0 0 * * * *", "2023-11-05 00:00", overlapOnce,
+			[]string{"2023-11-05 01:00 -0400", "2023-11-05 02:00 -0500", "2023-11-05 03:00 -0500"}},
+		{"America/New_York", "0 0 * * * *", "2023-11-05 00:00", overlapSkip,
+			[]string{"2023-11-05 02:00 -0500", "2023-11-05 03:00 -0500"}},
+		{"America/New_York", "0 0 * * * *", "2023-03-12 00:00", once,
+			[]string{"2023-03-12 01:00 -0500", "2023-03-12 03:00 -0400", "2023-03-12 04:00 -0400"}},
+		// A gap is found across several transitions.
+		{"America/New_York", "0 30 2 12 3 *", "2022-06-01 00:00", DSTPolicy{},
+			[]string{"2024-03-12 02:30 -0400"}},
+		{"America/New_York", "0 30 2 12 3 *", "2022-06-01 00:00", gapOnce,
+			[]string{"2023-03-12 03:00 -0400", "2024-03-12 02:30 -0400"}},
+
+		// Europe/London: 2023-03-26 01:00 -> 02:00, 2023-10-29 02:00 -> 01:00.
+		{"Europe/London", "0 30 1 * * *", "2023-03-25 12:00", DSTPolicy{},
+			[]string{"2023-03-27 01:30 +0100", "2023-03-28 01:30 +0100"}},
+		{"Europe/London", "0 30 1 * * *", "2023-03-25 12:00", gapOnce,
+			[]string{"2023-03-26 02:00 +0100", "2023-03-27 01:30 +0100"}},
+		{"Europe/London", "0 30 1 * * *", "2023-10-28 12:00", DSTPolicy{},
+			[]string{"2023-10-29 01:30 +0100", "2023-10-29 01:30 +0000", "2023-10-30 01:30 +0000"}},
+		{"Europe/London", "0 30 1 * * *", "2023-10-28 12:00", once,
+			[]string{"2023-10-29 01:30 +0100", "2023-10-30 01:30 +0000"}},
+
+		// Australia/Sydney: 2023-04-02 03:00 -> 02:00, 2023-10-01 02:00 -> 03:00.
+		{"Australia/Sydney", "0 30 2 * * *", "2023-04-01 12:00", DSTPolicy{},
+			[]string{"2023-04-02 02:30 +1100", "2023-04-02 02:30 +1000", "2023-04-03 02:30 +1000"}},
+		{"Australia/Sydney", "0 30 2 * * *", "2023-04-01 12:00", once,
+			[]string{"2023-04-02 02:30 +1100", "2023-04-03 02:30 +1000"}},
+		{"Australia/Sydney", "0 30 2 * * *", "2023-09-30 12:00", DSTPolicy{},
+			[]string{"2023-10-02 02:30 +1100"}},
+		{"Australia/Sydney", "0 30 2 * * *", "2023-09-30 12:00", once,
+			[]string{"2023-10-01 03:00 +1100", "2023-10-02 02:30 +1100"}},
+
+		// Australia/Lord_Howe shifts by 30 minutes: 2023-04-02 02:00 -> 01:30,
+		// 2023-10-01 02:00 -> 02:30.
+		{"Australia/Lord_Howe", "0 45 1 * * *", "2023-04-01 12:00", DSTPolicy{},
+			[]string{"2023-04-02 01:45 +1100", "2023-04-02 01:45 +1030", "2023-04-03 01:45 +1030"}},
+		{"Australia/Lord_Howe", "0 45 1 * * *", "2023-04-01 12:00", overlapOnce,
+			[]string{"2023-04-02 01:45 +1100", "2023-04-03 01:45 +1030"}},
+		{"Australia/Lord_Howe", "0 15 2 * * *", "2023-09-30 12:00", DSTPolicy{},
+			[]string{"2023-10-02 02:15 +1100"}},
+		{"Australia/Lord_Howe", "0 15 2 * * *", "2023-09-30 12:00", gapOnce,
+			[]string{"2023-10-01 02:30 +1100", "2023-10-02 02:15 +1100"}},
+
+		// America/Sao_Paulo skipped midnight: 2018-11-04 00:00 -> 01:00.
+		{"America/Sao_Paulo", "0 0 0 * * *", "2018-11-03 12:00", DSTPolicy{},
+			[]string{"2018-11-05 00:00 -0200"}},
+		{"America/Sao_Paulo", "0 0 0 * * *", "2018-11-03 12:00", gapOnce,
+			[]string{"2018-11-04 01:00 -0200", "2018-11-05 00:00 -0200"}},
+
+		// Asia/Shanghai has no DST: every policy is the same.
+		{"Asia/Shanghai", "0 30 2 * * *", "2023-03-11 00:00", once,
+			[]string{"2023-03-11 02:30 +0800", "2023-03-12 02:30 +0800"}},
+		{"Asia/Shanghai", "0 30 2 * * *", "2023-03-11 00:00", overlapSkip,
+			[]string{"2023-03-11 02:30 +0800", "2023-03-12 02:30 +0800"}},
+	}
+
+	const layout = "2006-01-02 15:04 -0700"
+	for _, test := range tests {
+		loc, err := time.LoadLocation(test.zone)
+		if err != nil {
+			t.Fatal(err)
+		}
+		from, err := time.ParseInLocation("2006-01-02 15:04", test.from, loc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		schedule, err := secondParser.Parse("TZ=" + test.zone + " " + test.spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		spec := schedule.(*SpecSchedule)
+		spec.DST = test.policy
+
+		var got []string
+		for next := from; len(got) < len(test.want); {
+			next = spec.Next(next)
+			if next.IsZero() {
+				break
+			}
+			got = append(got, next.In(loc).Format(layout))
+		}
+		if strings.Join(got, ", ") != strings.Join(test.want, ", ") {
+			t.Errorf("%s %q from %s with %+v:\n got %v\nwant %v", test.zone, test.spec, test.from, test.policy, got, test.want)
+		}
+	}
+}
+
+func TestEntryLocationAndDST(t *testing.T) {
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	cron := New(WithParser(secondParser), WithLocation(time.UTC), WithLogger(DiscardLogger),
+		WithLockProvider(NoopLockProvider()))
+	policy := DSTPolicy{Gap: DSTRunOnce}
+	cron.AddSingleton("0 0 9 * * *", func() {}, "tokyo", WithEntryLocation(tokyo), WithDST(policy))
+	cron.AddSingleton("0 0 9 * * *", func() {}, "default")
+	cron.Schedule(Every(time.Hour), FuncJob(func() {}), "every", WithEntryLocation(tokyo))
+
+	check := func() {
+		e, _ := cron.EntryByName("tokyo")
+		spec, ok := e.Schedule.(*SpecSchedule)
+		if !ok || spec.Location != tokyo || spec.DST != policy {
+			t.Errorf("expected the entry location and policy, got %+v", e.Schedule)
+		}
+		next := spec.Next(time.Date(2022, 12, 31, 12, 0, 0, 0, time.UTC))
+		if want := time.Date(2023, 1, 1, 9, 0, 0, 0, tokyo); !next.Equal(want) {
+			t.Errorf("expected %v, got %v", want, next)
+		}
+	}
+	check()
+	if e, _ := cron.EntryByName("default"); e.Schedule.(*SpecSchedule).Location != time.Local {
+		t.Errorf("expected the default entry to keep the Cron's location, got %v", e.Schedule.(*SpecSchedule).Location)
+	}
+	if e, _ := cron.EntryByName("every"); e.Schedule != Every(time.Hour) {
+		t.Errorf("expected other schedules unchanged, got %+v", e.Schedule)
+	}
+
+	// Rescheduling keeps the entry's location and policy.
+	if err := cron.Reschedule("tokyo", "0 0 9 * * *"); err != nil {
+		t.Fatal(err)
+	}
+	check()
+}
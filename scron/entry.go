@@ -2,16 +2,19 @@ package scron
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	scron "github.com/henryxu/tools/scron/cron_locker"
 	"math"
 	"time"
 
 	"github.com/henryxu/tools/alarm"
 	"github.com/henryxu/tools/common"
-	"github.com/henryxu/tools/sys_info"
+	"github.com/henryxu/tools/redis_locker"
+	scron "github.com/henryxu/tools/scron/cron_locker"
 )
 
+// TaskLockError is the message of cron_locker.ErrTaskLocked. Compare errors
+// with errors.Is rather than by message.
 var TaskLockError = "lock Taskkey failed"
 
 // Status returns the status of entry.
@@ -37,32 +40,25 @@ func getSleepTime(cpuFl, memoryFl float64) int {
 	}
 	return cpu + mem
 }
-func (entry *Entry) getLock() bool {
+func (entry *Entry) getLock(at time.Time) error {
 	// 根据任务时间生成过期时间
-	ttl := entry.getGapTime(entry.Next)
-	sysInfo := sys_info.GetSysInfo()
-	serverIp := sysInfo.BestServerIp()
-	// 不是最佳服务ip
-	if serverIp != sysInfo.Ip {
-		// 根据负载生成一个睡眠时间
-		time.Sleep(time.Duration(getSleepTime(sysInfo.Cpu, sysInfo.Memory)) * time.Millisecond)
-	}
-
-	key := entry.GetCronExecKey(entry.Next)
+	ttl := entry.getGapTime(at)
+	key := entry.GetCronExecKey(at)
 	taskKey := entry.GetTaskExecKey()
-	// 理想状态下分配给状态最佳的服务
-	redisLocker := scron.NewRedisLocker(key, taskKey, ttl, scron.NewRedisClient())
-	if err := redisLocker.Lock(); err != nil {
-		if TaskLockError == err.Error() {
+	locker, err := entry.lockProvider.Acquire(key, taskKey, ttl)
+	if err != nil {
+		if errors.Is(err, scron.ErrTaskLocked) {
 			alarmIns := alarm.GetAlarmInstance()
 			if common.RunMode == "prod" {
 				alarmIns.SendAlarm(fmt.Sprintf("slp-tools.任务:%s,在下一个执行期未结束，请及时处理！@all", entry.Name), "slp-tools.cron.alarm:"+entry.Name, 5*time.Minute)
 			}
 		}
-		return false
+		return err
 	}
-	entry.Locker = redisLocker
-	return true
+	entry.Locker = locker
+	entry.lockDeadline = time.Now().Add(time.Duration(ttl) * time.Second)
+	entry.lockKey = key
+	return nil
 }
 
 // checkFinal
@@ -89,7 +85,11 @@ LoopTwice:
 func (entry *Entry) getGapTime(now time.Time) int {
 	nextTime := entry.Schedule.Next(now)
 	subTime := nextTime.Unix() - now.Unix()
-	if subTime <= 60*60*24 {
+	if !nextTime.IsZero() && subTime <= 60*60*24 {
+		// 毫秒级任务至少锁1秒，0表示永不过期
+		if subTime < 1 {
+			return 1
+		}
 		return int(subTime)
 	} else {
 		// 如果执行时长超过一天
@@ -97,8 +97,11 @@ func (entry *Entry) getGapTime(now time.Time) int {
 	}
 }
 
-// 获取key 任务名+日期
+// 获取key 任务名+日期，毫秒级任务附加毫秒
 func (entry *Entry) GetCronExecKey(now time.Time) string {
+	if ms := now.Nanosecond() / int(time.Millisecond); ms > 0 {
+		return fmt.Sprintf("cron_%s%v.%03d", entry.Name, now.Format(common.SecondPrettyStrFormat), ms)
+	}
 	return fmt.Sprintf("cron_%s%v", entry.Name, now.Format(common.SecondPrettyStrFormat))
 }
 
@@ -107,8 +110,11 @@ func (entry *Entry) GetTaskExecKey() string {
 	return fmt.Sprintf("exec_%s", entry.Name)
 }
 
-func (entry *Entry) releaseLock() {
-	if err := entry.Locker.UnLock(); err != nil {
+func (entry *Entry) releaseLock(locker redis_locker.RedisLockInter) {
+	if locker == nil {
+		return
+	}
+	if err := locker.UnLock(); err != nil {
 		if common.RunMode == "prod" {
 			alarmIns := alarm.GetAlarmInstance()
 			alarmIns.SendAlarm(fmt.Sprintf("cron:%v,err:%v,请及时处理！@lion(里奥)", entry.Name, err), "slp-tools.redis.alarm", 5*time.Minute)
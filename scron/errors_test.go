@@ -0,0 +1,50 @@
+package scron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDuplicateNameAndNotFound(t *testing.T) {
+	for _, started := range []bool{false, true} {
+		cron := New(WithParser(secondParser), WithLogger(DiscardLogger), WithLockProvider(NoopLockProvider()))
+		if started {
+			cron.Start()
+		}
+		id, err := cron.AddSingleton("@yearly", func() {}, "job")
+		if err != nil || id == 0 {
+			t.Fatalf("expected the entry added, got %d %v", id, err)
+		}
+		if _, err := cron.AddSingleton("@daily", func() {}, "job"); err != ErrDuplicateName {
+			t.Errorf("expected ErrDuplicateName, got %v", err)
+		}
+		if _, err := cron.Schedule(Every(time.Hour), FuncJob(func() {}), "job"); err != ErrDuplicateName {
+			t.Errorf("expected ErrDuplicateName, got %v", err)
+		}
+
+		if e, err := cron.EntryByName("job"); err != nil || e.ID != id {
+			t.Errorf("expected entry %d, got %+v %v", id, e, err)
+		}
+		if _, err := cron.EntryByName("missing"); err != ErrNotFound {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+		if err := cron.RemoveByName("missing"); err != ErrNotFound {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+		if err := cron.Remove(id + 100); err != ErrNotFound {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+
+		if err := cron.RemoveByName("job"); err != nil {
+			t.Errorf("expected the entry removed, got %v", err)
+		}
+		if err := cron.Remove(id); err != ErrNotFound {
+			t.Errorf("expected the entry gone, got %v", err)
+		}
+		// The name is free again.
+		if _, err := cron.AddSingleton("@daily", func() {}, "job"); err != nil {
+			t.Errorf("expected the name reusable, got %v", err)
+		}
+		cron.Stop()
+	}
+}
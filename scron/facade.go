@@ -1,5 +1,7 @@
 package scron
 
+import "context"
+
 var (
 	defaultCron = New()
 )
@@ -7,15 +9,21 @@ var (
 // AddSingleton adds a func to the Cron to be run on the given schedule.
 // The spec is parsed using the time zone of this Cron instance as the default.
 // An opaque ID is returned that can be used to later remove it.
-func AddSingleton(spec string, cmd func(), cmdName string) (EntryID, error) {
-	return defaultCron.AddJob(spec, FuncJob(cmd), cmdName)
+func AddSingleton(spec string, cmd func(), cmdName string, opts ...EntryOption) (EntryID, error) {
+	return defaultCron.AddJob(spec, FuncJob(cmd), cmdName, opts...)
 }
 
 // Add adds a func to the Cron to be run on the given schedule.
 // The spec is parsed using the time zone of this Cron instance as the default.
 // An opaque ID is returned that can be used to later remove it.
-func Add(spec string, cmd func(), cmdName string) (EntryID, error) {
-	return defaultCron.AddJob(spec, FuncJob(cmd), cmdName)
+func Add(spec string, cmd func(), cmdName string, opts ...EntryOption) (EntryID, error) {
+	return defaultCron.AddJob(spec, FuncJob(cmd), cmdName, opts...)
+}
+
+// AddContext adds a context-aware func to the Cron to be run on the given
+// schedule. The context is cancelled on Stop, on removal and on timeout.
+func AddContext(spec string, cmd func(ctx context.Context) error, cmdName string, opts ...EntryOption) (EntryID, error) {
+	return defaultCron.AddContextJob(spec, FuncContextJob(cmd), cmdName, opts...)
 }
 
 // Entries return all timed tasks as slice.
@@ -36,15 +44,13 @@ func Run() {
 }
 
 // Remove all timed tasks as slice.
-func Remove(id EntryID) {
-	defaultCron.Remove(id)
-	return
+func Remove(id EntryID) error {
+	return defaultCron.Remove(id)
 }
 
 // RemoveByName all timed tasks as slice.
-func RemoveByName(name string) {
-	defaultCron.RemoveByName(name)
-	return
+func RemoveByName(name string) error {
+	return defaultCron.RemoveByName(name)
 }
 
 // Stop caller can wait for running jobs to complete.
@@ -52,3 +58,33 @@ func Stop() {
 	defaultCron.Stop()
 	return
 }
+
+// History returns up to limit of the most recent runs of the named job.
+func History(name string, limit int) ([]Record, error) {
+	return defaultCron.History(name, limit)
+}
+
+// Pause stops the named task from being run on schedule.
+func Pause(name string) error {
+	return defaultCron.Pause(name)
+}
+
+// Resume lets a paused task run on schedule again.
+func Resume(name string) error {
+	return defaultCron.Resume(name)
+}
+
+// Reschedule replaces the schedule of the named task, keeping its state.
+func Reschedule(name, spec string) error {
+	return defaultCron.Reschedule(name, spec)
+}
+
+// UpdateJob replaces the func of the named task, keeping its state.
+func UpdateJob(name string, cmd func()) error {
+	return defaultCron.UpdateJob(name, FuncJob(cmd))
+}
+
+// Trigger runs the named task now, out of schedule.
+func Trigger(name string) error {
+	return defaultCron.Trigger(name)
+}
@@ -0,0 +1,176 @@
+package scron
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/henryxu/tools/common"
+	"github.com/henryxu/tools/sys_info"
+)
+
+// ErrNoHistory is returned by Cron.History when no HistoryStore is configured.
+var ErrNoHistory = errors.New("scron: no history store configured")
+
+// Outcomes recorded in the job history.
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// defaultHistorySize is how many records are kept per job by default.
+const defaultHistorySize = 100
+
+// Record is one finished run as kept in the job history.
+type Record struct {
+	Name      string        `json:"name"`
+	Host      string        `json:"host"`
+	Scheduled time.Time     `json:"scheduled"`
+	Start     time.Time     `json:"start"`
+	End       time.Time     `json:"end"`
+	Duration  time.Duration `json:"duration"`
+	Outcome   string        `json:"outcome"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// newRecord converts a run result into a history record for this host.
+func newRecord(r Result) Record {
+	record := Record{
+		Name:      r.Name,
+		Host:      sys_info.LocalIP(),
+		Scheduled: r.Scheduled,
+		Start:     r.Start,
+		End:       r.End,
+		Duration:  r.Duration(),
+		Outcome:   OutcomeSuccess,
+	}
+	if r.Err != nil {
+		record.Outcome = OutcomeFailure
+		record.Error = r.Err.Error()
+	}
+	return record
+}
+
+// HistoryStore keeps the most recent runs of every job.
+type HistoryStore interface {
+	// Add appends a record to the history of record.Name.
+	Add(record Record) error
+
+	// List returns up to limit records of the named job, newest first.
+	// A limit of zero or less returns everything kept.
+	List(name string, limit int) ([]Record, error)
+}
+
+// memoryHistory keeps the history in process memory.
+type memoryHistory struct {
+	mu      sync.Mutex
+	size    int
+	records map[string][]Record
+}
+
+// MemoryHistory returns a HistoryStore that keeps the last size records of
+// each job in memory. A size of zero or less keeps 100.
+func MemoryHistory(size int) HistoryStore {
+	if size <= 0 {
+		size = defaultHistorySize
+	}
+	return &memoryHistory{size: size, records: make(map[string][]Record)}
+}
+
+func (h *memoryHistory) Add(record Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	records := append([]Record{record}, h.records[record.Name]...)
+	if len(records) > h.size {
+		records = records[:h.size]
+	}
+	h.records[record.Name] = records
+	return nil
+}
+
+func (h *memoryHistory) List(name string, limit int) ([]Record, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	records := h.records[name]
+	if limit > 0 && limit < len(records) {
+		records = records[:limit]
+	}
+	return append([]Record(nil), records...), nil
+}
+
+// redisHistory keeps the history in a capped Redis list per job, so that
+// every node of the cluster sees the same history.
+type redisHistory struct {
+	client redis.UniversalClient
+	size   int
+}
+
+// RedisHistory returns a HistoryStore that keeps the last size records of
+// each job in Redis. A nil client falls back to common.NewRedisClient and a
+// size of zero or less keeps 100.
+func RedisHistory(client redis.UniversalClient, size int) HistoryStore {
+	if size <= 0 {
+		size = defaultHistorySize
+	}
+	return &redisHistory{client: client, size: size}
+}
+
+// 获取key 任务名
+func historyKey(name string) string {
+	return fmt.Sprintf("history_%s", name)
+}
+
+func (h *redisHistory) redis() redis.UniversalClient {
+	if h.client == nil {
+		return common.NewRedisClient()
+	}
+	return h.client
+}
+
+func (h *redisHistory) Add(record Record) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	key := historyKey(record.Name)
+	_, err = h.redis().TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.LPush(ctx, key, value)
+		pipe.LTrim(ctx, key, 0, int64(h.size-1))
+		return nil
+	})
+	return err
+}
+
+func (h *redisHistory) List(name string, limit int) ([]Record, error) {
+	stop := int64(limit - 1)
+	if limit <= 0 {
+		stop = -1
+	}
+	values, err := h.redis().LRange(context.Background(), historyKey(name), 0, stop).Result()
+	if err != nil {
+		return nil, err
+	}
+	records := make([]Record, 0, len(values))
+	for _, value := range values {
+		var record Record
+		if err := json.Unmarshal([]byte(value), &record); err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// History returns up to limit of the most recent runs of the named job,
+// newest first.
+func (c *Cron) History(name string, limit int) ([]Record, error) {
+	if c.history == nil {
+		return nil, ErrNoHistory
+	}
+	return c.history.List(name, limit)
+}
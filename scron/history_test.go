@@ -0,0 +1,78 @@
+package scron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+func testHistoryStore(t *testing.T, store HistoryStore) {
+	for i := 0; i < 5; i++ {
+		if err := store.Add(Record{Name: "job", Outcome: OutcomeSuccess, Error: fmt.Sprint(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	store.Add(Record{Name: "other"})
+
+	records, err := store.List("job", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].Error != "4" || records[1].Error != "3" {
+		t.Errorf("expected the 2 newest records, got %+v", records)
+	}
+
+	// Only the last 3 are kept.
+	records, _ = store.List("job", 0)
+	if len(records) != 3 || records[2].Error != "2" {
+		t.Errorf("expected 3 records kept, got %+v", records)
+	}
+
+	records, _ = store.List("missing", 10)
+	if len(records) != 0 {
+		t.Errorf("expected no records, got %+v", records)
+	}
+}
+
+func TestMemoryHistory(t *testing.T) {
+	testHistoryStore(t, MemoryHistory(3))
+}
+
+func TestRedisHistory(t *testing.T) {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	testHistoryStore(t, RedisHistory(client, 3))
+}
+
+func TestCronHistory(t *testing.T) {
+	results := make(chan Result, 1)
+	cron := New(WithParser(secondParser), WithLockProvider(NoopLockProvider()), WithLogger(DiscardLogger),
+		WithHistory(MemoryHistory(10)), WithResultHooks(func(r Result) { results <- r }))
+	if _, err := New().History("history", 1); err != ErrNoHistory {
+		t.Errorf("expected %v, got %v", ErrNoHistory, err)
+	}
+
+	cron.AddContextJob("* * * * * ?", FuncContextJob(func(ctx context.Context) error {
+		return errors.New("boom")
+	}), "history")
+	cron.Start()
+	defer cron.Stop()
+
+	select {
+	case <-time.After(OneSecond):
+		t.Fatal("expected a run")
+	case <-results:
+	}
+	records, err := cron.History("history", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Outcome != OutcomeFailure || records[0].Error != "boom" {
+		t.Errorf("expected the failed run to be recorded, got %+v", records)
+	}
+}
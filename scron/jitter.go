@@ -0,0 +1,151 @@
+package scron
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JitterSchedule delays every activation of a schedule by a fixed offset,
+// e.g. "Every hour, at 17m23s past".
+type JitterSchedule struct {
+	Schedule Schedule
+	Offset   time.Duration
+}
+
+// Jitter returns schedule delayed by an offset below max derived from key, so
+// that jobs with different keys, usually their names, spread over max instead
+// of all firing at :00, while each one keeps a fixed period and every node
+// agrees on its activations.
+func Jitter(schedule Schedule, key string, max time.Duration) JitterSchedule {
+	var offset time.Duration
+	if max > 0 {
+		offset = time.Duration(uint64(hashKey(key)) * uint64(time.Millisecond) % uint64(max))
+		offset -= offset % time.Millisecond
+	}
+	return JitterSchedule{Schedule: schedule, Offset: offset}
+}
+
+// Unwrap returns the delayed schedule.
+func (s JitterSchedule) Unwrap() Schedule {
+	return s.Schedule
+}
+
+// Next returns the next activation of the schedule, delayed by the offset.
+func (s JitterSchedule) Next(t time.Time) time.Time {
+	next := s.Schedule.Next(t.Add(-s.Offset))
+	if next.IsZero() {
+		return next
+	}
+	return next.Add(s.Offset)
+}
+
+// spread derives the H expressions of the entry's schedule from its name and
+// applies its jitter, see WithJitter.
+func (e *Entry) spread(schedule Schedule) Schedule {
+	if spec, ok := schedule.(*SpecSchedule); ok {
+		schedule = spec.hashed(e.Name)
+	}
+	if e.jitter > 0 {
+		schedule = Jitter(schedule, e.Name, e.jitter)
+	}
+	return schedule
+}
+
+// hashed returns s with the H expressions of its spec derived from key.
+func (s *SpecSchedule) hashed(key string) *SpecSchedule {
+	if s.hashFields == nil {
+		return s
+	}
+	h, err := parseSpec(s.hashFields, s.Location, key)
+	if err != nil {
+		return s
+	}
+	h.DST = s.DST
+	return h
+}
+
+// hashKey returns a stable hash of key.
+func hashKey(key string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return h.Sum32()
+}
+
+// fieldBounds are the bounds of the normalized fields, in order.
+var fieldBounds = []bounds{seconds, minutes, hours, dom, months, dow, years}
+
+// hashField replaces the H expressions of the i-th field with values derived
+// from key, reporting whether there were any:
+//
+//	H          a value within the field's bounds, 1-28 for the day of month
+//	H(a-b)     a value within a-b
+//	H/n        every n, from a value below n
+//	H(a-b)/n   every n within a-b, from a value below a+n
+func hashField(field string, i int, key string) (string, bool, error) {
+	exprs := strings.Split(field, ",")
+	hashed := false
+	for j, expr := range exprs {
+		if !strings.HasPrefix(expr, "H") {
+			continue
+		}
+		resolved, err := hashExpr(expr, fieldBounds[i], key+"#"+strconv.Itoa(i))
+		if err != nil {
+			return "", false, err
+		}
+		exprs[j] = resolved
+		hashed = true
+	}
+	return strings.Join(exprs, ","), hashed, nil
+}
+
+func hashExpr(expr string, r bounds, key string) (string, error) {
+	low, high := r.min, r.max
+	if r.min == dom.min && r.max == dom.max {
+		high = 28
+	}
+	rest := expr[1:]
+	if strings.HasPrefix(rest, "(") {
+		end := strings.Index(rest, ")")
+		if end < 0 {
+			return "", fmt.Errorf("unclosed range: %s", expr)
+		}
+		lowAndHigh := strings.Split(rest[1:end], "-")
+		if len(lowAndHigh) != 2 {
+			return "", fmt.Errorf("expected a range in parentheses: %s", expr)
+		}
+		var err error
+		if low, err = mustParseInt(lowAndHigh[0]); err != nil {
+			return "", err
+		}
+		if high, err = mustParseInt(lowAndHigh[1]); err != nil {
+			return "", err
+		}
+		if low < r.min || high > r.max || low > high {
+			return "", fmt.Errorf("invalid range (%d-%d) within %d-%d: %s", low, high, r.min, r.max, expr)
+		}
+		rest = rest[end+1:]
+	}
+
+	h := uint(hashKey(key))
+	switch {
+	case rest == "":
+		return strconv.Itoa(int(low + h%(high-low+1))), nil
+	case strings.HasPrefix(rest, "/"):
+		step, err := mustParseInt(rest[1:])
+		if err != nil {
+			return "", err
+		}
+		if step == 0 {
+			return "", fmt.Errorf("step of range should be a positive number: %s", expr)
+		}
+		span := step
+		if span > high-low+1 {
+			span = high - low + 1
+		}
+		return fmt.Sprintf("%d-%d/%d", low+h%span, high, step), nil
+	}
+	return "", fmt.Errorf("failed to parse hash expression: %s", expr)
+}
@@ -0,0 +1,99 @@
+package scron
+
+import (
+	"math/bits"
+	"testing"
+	"time"
+)
+
+func TestJitter(t *testing.T) {
+	hourly, _ := ParseStandard("@hourly")
+	offsets := make(map[time.Duration]bool)
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		s := Jitter(hourly, name, time.Hour)
+		if s.Offset < 0 || s.Offset >= time.Hour {
+			t.Errorf("%s: offset %v out of bounds", name, s.Offset)
+		}
+		if again := Jitter(hourly, name, time.Hour); again.Offset != s.Offset {
+			t.Errorf("%s: expected a stable offset, got %v and %v", name, s.Offset, again.Offset)
+		}
+		offsets[s.Offset] = true
+	}
+	if len(offsets) < 2 {
+		t.Errorf("expected the offsets to spread, got %v", offsets)
+	}
+
+	s := JitterSchedule{Schedule: hourly, Offset: 90 * time.Second}
+	tests := []struct{ from, expected string }{
+		{"Mon Jul 9 10:00 2012", "Mon Jul 9 10:01:30 2012"},
+		{"Mon Jul 9 10:01:30 2012", "Mon Jul 9 11:01:30 2012"},
+		{"Mon Jul 9 10:01:29 2012", "Mon Jul 9 10:01:30 2012"},
+	}
+	for _, test := range tests {
+		if actual := s.Next(getTime(test.from)); !actual.Equal(getTime(test.expected)) {
+			t.Errorf("from %s: expected %s, got %v", test.from, test.expected, actual)
+		}
+	}
+}
+
+func TestHashExpressions(t *testing.T) {
+	sched, err := ParseStandard("H H(9-17) H * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec := sched.(*SpecSchedule)
+	minutes := make(map[uint64]bool)
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		h := spec.hashed(name)
+		if again := spec.hashed(name); again.Minute != h.Minute || again.Hour != h.Hour {
+			t.Errorf("%s: expected stable fields", name)
+		}
+		if bits.OnesCount64(h.Minute) != 1 || h.Hour&^getBits(9, 17, 1) != 0 || h.Dom&^getBits(1, 28, 1) != 0 {
+			t.Errorf("%s: fields out of bounds: %+v", name, h)
+		}
+		minutes[h.Minute] = true
+	}
+	if len(minutes) < 2 {
+		t.Errorf("expected the minutes to spread, got %v", minutes)
+	}
+
+	sched, err = ParseStandard("H/15 * * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := sched.(*SpecSchedule).hashed("job")
+	if bits.OnesCount64(h.Minute) != 4 || bits.TrailingZeros64(h.Minute) >= 15 {
+		t.Errorf("expected every 15 minutes from below 15, got %b", h.Minute)
+	}
+
+	for _, spec := range []string{"H(5-2) * * * *", "H(0-60) * * * *", "H(1) * * * *", "H/0 * * * *", "Hx * * * *", "H(1-2 * * * *"} {
+		if _, err := ParseStandard(spec); err == nil {
+			t.Errorf("expected an error parsing %s", spec)
+		}
+	}
+}
+
+func TestEntryHashAndJitter(t *testing.T) {
+	cron := New(WithLogger(DiscardLogger), WithLockProvider(NoopLockProvider()))
+	cron.AddSingleton("H * * * *", func() {}, "a")
+	cron.AddSingleton("0 * * * *", func() {}, "b", WithJitter(time.Minute))
+
+	a, _ := cron.EntryByName("a")
+	spec, _ := ParseStandard("H * * * *")
+	if a.Schedule.(*SpecSchedule).Minute != spec.(*SpecSchedule).hashed("a").Minute {
+		t.Errorf("expected the minute derived from the entry name")
+	}
+	b, _ := cron.EntryByName("b")
+	jitter, ok := b.Schedule.(JitterSchedule)
+	if !ok || jitter.Offset != Jitter(nil, "b", time.Minute).Offset {
+		t.Errorf("expected the schedule delayed by the name's offset, got %#v", b.Schedule)
+	}
+
+	if err := cron.Reschedule("b", "30 * * * *"); err != nil {
+		t.Fatal(err)
+	}
+	b, _ = cron.EntryByName("b")
+	if _, ok := b.Schedule.(JitterSchedule); !ok {
+		t.Errorf("expected the jitter kept on reschedule, got %#v", b.Schedule)
+	}
+}
@@ -0,0 +1,145 @@
+package scron
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/henryxu/tools/redis_locker"
+	scron "github.com/henryxu/tools/scron/cron_locker"
+	"github.com/henryxu/tools/sys_info"
+)
+
+// LockProvider hands out the locks that keep an entry from running on more
+// than one node for the same activation.
+type LockProvider interface {
+	// Acquire takes the lock for one run. key identifies the activation
+	// (see GetCronExecKey), taskKey the job itself (see GetTaskExecKey) and
+	// ttl is the lock lifetime in seconds. The returned locker is released
+	// once the job completes.
+	//
+	// A lost activation is reported as cron_locker.ErrKeyLocked and a job
+	// still running as cron_locker.ErrTaskLocked, possibly wrapped. Any
+	// other error is taken as a failure of the provider and logged at Error.
+	Acquire(key, taskKey string, ttl int) (redis_locker.RedisLockInter, error)
+}
+
+// LockOwner is implemented by LockProviders that can tell who holds the lock
+// of a job, as shown by the admin API.
+type LockOwner interface {
+	// Owner returns the token of the run holding taskKey, or "" if free.
+	Owner(taskKey string) (string, error)
+}
+
+type fencingTokenKey struct{}
+
+// FencingToken returns the fencing token issued by the lock of the current
+// run. Tokens of an entry increase with every run across the cluster, so a
+// store that remembers the highest token it has seen can reject writes from
+// a run whose lock has expired and been taken over. It is false when the
+// LockProvider does not issue tokens, e.g. NoopLockProvider.
+//
+// With RedisLockProvider the counter is the key {exec_<name>}:fence. It has
+// no TTL and is meant to outlive runs and the entry itself: deleting it
+// restarts the tokens at 1, below those stores have already seen. Delete it
+// by hand only once no store remembers tokens of the job.
+func FencingToken(ctx context.Context) (int64, bool) {
+	token, ok := ctx.Value(fencingTokenKey{}).(int64)
+	return token, ok
+}
+
+// fencingToken returns the token issued by locker, or 0.
+func fencingToken(locker redis_locker.RedisLockInter) int64 {
+	if fenced, ok := locker.(redis_locker.FencedLock); ok {
+		return fenced.FencingToken()
+	}
+	return 0
+}
+
+// lockToken identifies this host in the locks it takes.
+func lockToken() string {
+	return fmt.Sprintf("token_%s_%d", sys_info.LocalIP(), time.Now().UnixNano())
+}
+
+// redisLockProvider locks through Redis so that only one node in the
+// cluster runs an activation.
+type redisLockProvider struct {
+	client redis.UniversalClient
+}
+
+// RedisLockProvider returns a LockProvider backed by the given Redis client,
+// which may be a single node, Sentinel or Cluster client. A nil client falls
+// back to the one registered in common (see common.SetRedisClient).
+func RedisLockProvider(client redis.UniversalClient) LockProvider {
+	return &redisLockProvider{client: client}
+}
+
+func (p *redisLockProvider) Acquire(key, taskKey string, ttl int) (redis_locker.RedisLockInter, error) {
+	sysInfo := sys_info.GetSysInfo()
+	serverIp := sysInfo.BestServerIp()
+	// 不是最佳服务ip
+	if serverIp != sysInfo.Ip {
+		// 根据负载生成一个睡眠时间
+		time.Sleep(time.Duration(getSleepTime(sysInfo.Cpu, sysInfo.Memory)) * time.Millisecond)
+	}
+
+	client := p.client
+	if client == nil {
+		client = scron.NewRedisClient()
+	}
+	// 理想状态下分配给状态最佳的服务
+	locker := scron.NewRedisLocker(key, taskKey, ttl, client, scron.WithToken(lockToken()))
+	if err := locker.Lock(); err != nil {
+		return nil, err
+	}
+	return locker, nil
+}
+
+func (p *redisLockProvider) Owner(taskKey string) (string, error) {
+	client := p.client
+	if client == nil {
+		client = scron.NewRedisClient()
+	}
+	owner, err := client.Get(context.Background(), taskKey).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return owner, err
+}
+
+// localLockProvider locks within the current process only.
+type localLockProvider struct {
+	store *scron.LocalStore
+}
+
+// LocalLockProvider returns a LockProvider that guards entries within the
+// current process, for single-node services that do not run Redis.
+func LocalLockProvider() LockProvider {
+	return &localLockProvider{store: scron.NewLocalStore()}
+}
+
+func (p *localLockProvider) Acquire(key, taskKey string, ttl int) (redis_locker.RedisLockInter, error) {
+	locker := scron.NewLocalLocker(p.store, key, taskKey, ttl)
+	if err := locker.Lock(); err != nil {
+		return nil, err
+	}
+	return locker, nil
+}
+
+func (p *localLockProvider) Owner(taskKey string) (string, error) {
+	return p.store.Owner(taskKey), nil
+}
+
+// noopLockProvider never refuses a lock.
+type noopLockProvider struct{}
+
+// NoopLockProvider returns a LockProvider that performs no locking at all,
+// mostly useful in tests.
+func NoopLockProvider() LockProvider {
+	return noopLockProvider{}
+}
+
+func (noopLockProvider) Acquire(key, taskKey string, ttl int) (redis_locker.RedisLockInter, error) {
+	return scron.NewNoopLocker(), nil
+}
@@ -0,0 +1,96 @@
+package scron
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLocalLockProvider(t *testing.T) {
+	p := LocalLockProvider()
+
+	locker, err := p.Acquire("cron_job20240101000000", "exec_job", 60)
+	if err != nil {
+		t.Fatal("first acquire failed:", err)
+	}
+
+	// Same activation on another "node" must lose.
+	if _, err := p.Acquire("cron_job20240101000000", "exec_job", 60); err == nil {
+		t.Error("expected second acquire of the same activation to fail")
+	}
+
+	// Next activation while the job is still running hits the task key.
+	_, err = p.Acquire("cron_job20240101000100", "exec_job", 60)
+	if err == nil || err.Error() != TaskLockError {
+		t.Errorf("expected %q, got %v", TaskLockError, err)
+	}
+
+	if err := locker.UnLock(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Acquire("cron_job20240101000100", "exec_job", 60); err != nil {
+		t.Error("expected acquire after unlock to succeed:", err)
+	}
+	// The finished activation stays locked until it expires.
+	if _, err := p.Acquire("cron_job20240101000000", "exec_job2", 60); err == nil {
+		t.Error("expected finished activation to stay locked")
+	}
+}
+
+func TestNoopLockProvider(t *testing.T) {
+	p := NoopLockProvider()
+	for i := 0; i < 2; i++ {
+		if _, err := p.Acquire("cron_job", "exec_job", 60); err != nil {
+			t.Error("noop acquire failed:", err)
+		}
+	}
+}
+
+func TestCronWithLocalLockProvider(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+
+	cron := New(WithParser(secondParser), WithChain(), WithLockProvider(LocalLockProvider()))
+	cron.AddSingleton("* * * * * ?", func() { wg.Done() }, "local-lock")
+	cron.Start()
+	defer cron.Stop()
+
+	select {
+	case <-time.After(OneSecond):
+		t.Error("expected job to run")
+	case <-wait(wg):
+	}
+}
+
+func TestFencingToken(t *testing.T) {
+	tokens := make(chan int64, 10)
+	cron := New(WithParser(secondParser), WithChain(), WithLockProvider(LocalLockProvider()))
+	cron.AddContextJob("* * * * * ?", FuncContextJob(func(ctx context.Context) error {
+		token, _ := FencingToken(ctx)
+		if info, _ := RunInfoFromContext(ctx); info.FencingToken != token {
+			t.Errorf("expected the run info to carry token %d, got %d", token, info.FencingToken)
+		}
+		tokens <- token
+		return nil
+	}), "fenced")
+	cron.Start()
+	defer cron.Stop()
+
+	var prev int64
+	for i := 0; i < 2; i++ {
+		select {
+		case <-time.After(2 * OneSecond):
+			t.Fatal("expected job to run")
+		case token := <-tokens:
+			if token <= prev {
+				t.Fatalf("expected the token to increase past %d, got %d", prev, token)
+			}
+			prev = token
+		}
+	}
+
+	if _, ok := FencingToken(context.Background()); ok {
+		t.Error("expected no token outside a run")
+	}
+}
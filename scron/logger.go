@@ -24,37 +24,141 @@ type Logger interface {
 	Error(err error, msg string, keysAndValues ...interface{})
 }
 
+// LeveledLogger is implemented by loggers that also log at the debug and warn
+// levels. Cron logs its routine scheduling and activations taken by another
+// node at Debug, and runs still going when the next one is due at Warn; with
+// a plain Logger both go to Info.
+type LeveledLogger interface {
+	Logger
+	// Debug logs detailed messages, e.g. every wake up of the scheduler.
+	Debug(msg string, keysAndValues ...interface{})
+	// Warn logs unexpected but handled conditions.
+	Warn(msg string, keysAndValues ...interface{})
+}
+
+// ContextualLogger is implemented by loggers that can attach key/values to
+// every line they log, like logr's WithValues. Cron uses it to add the entry
+// name and run ID; other loggers are wrapped to prepend them.
+type ContextualLogger interface {
+	Logger
+	WithValues(keysAndValues ...interface{}) Logger
+}
+
+// LogLevel is the minimum level logged by LevelPrintfLogger.
+type LogLevel int
+
+const (
+	LevelDebug LogLevel = iota - 1
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
 // PrintfLogger wraps a Printf-based logger (such as the standard library "log")
 // into an implementation of the Logger interface which logs errors only.
 func PrintfLogger(l interface{ Printf(string, ...interface{}) }) Logger {
-	return printfLogger{l, false}
+	return printfLogger{l, LevelError}
 }
 
 // VerbosePrintfLogger wraps a Printf-based logger (such as the standard library
 // "log") into an implementation of the Logger interface which logs everything.
 func VerbosePrintfLogger(l interface{ Printf(string, ...interface{}) }) Logger {
-	return printfLogger{l, true}
+	return printfLogger{l, LevelDebug}
+}
+
+// LevelPrintfLogger wraps a Printf-based logger (such as the standard library
+// "log") into an implementation of the Logger interface which logs messages
+// of the given level and above.
+func LevelPrintfLogger(l interface{ Printf(string, ...interface{}) }, level LogLevel) Logger {
+	return printfLogger{l, level}
 }
 
 type printfLogger struct {
-	logger  interface{ Printf(string, ...interface{}) }
-	logInfo bool
+	logger interface{ Printf(string, ...interface{}) }
+	level  LogLevel
+}
+
+func (pl printfLogger) Debug(msg string, keysAndValues ...interface{}) {
+	pl.log(LevelDebug, msg, keysAndValues)
 }
 
 func (pl printfLogger) Info(msg string, keysAndValues ...interface{}) {
-	if pl.logInfo {
-		keysAndValues = formatTimes(keysAndValues)
-		pl.logger.Printf(
-			formatString(len(keysAndValues)),
-			append([]interface{}{msg}, keysAndValues...)...)
-	}
+	pl.log(LevelInfo, msg, keysAndValues)
+}
+
+func (pl printfLogger) Warn(msg string, keysAndValues ...interface{}) {
+	pl.log(LevelWarn, msg, append([]interface{}{"level", "warn"}, keysAndValues...))
 }
 
 func (pl printfLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	pl.log(LevelError, msg, append([]interface{}{"error", err}, keysAndValues...))
+}
+
+func (pl printfLogger) log(level LogLevel, msg string, keysAndValues []interface{}) {
+	if level < pl.level {
+		return
+	}
 	keysAndValues = formatTimes(keysAndValues)
 	pl.logger.Printf(
-		formatString(len(keysAndValues)+2),
-		append([]interface{}{msg, "error", err}, keysAndValues...)...)
+		formatString(len(keysAndValues)),
+		append([]interface{}{msg}, keysAndValues...)...)
+}
+
+// valuesLogger prepends key/values to every line of a Logger that cannot
+// attach them itself.
+type valuesLogger struct {
+	logger        Logger
+	keysAndValues []interface{}
+}
+
+func (vl valuesLogger) with(keysAndValues []interface{}) []interface{} {
+	return append(append([]interface{}(nil), vl.keysAndValues...), keysAndValues...)
+}
+
+func (vl valuesLogger) Debug(msg string, keysAndValues ...interface{}) {
+	logDebug(vl.logger, msg, vl.with(keysAndValues)...)
+}
+
+func (vl valuesLogger) Info(msg string, keysAndValues ...interface{}) {
+	vl.logger.Info(msg, vl.with(keysAndValues)...)
+}
+
+func (vl valuesLogger) Warn(msg string, keysAndValues ...interface{}) {
+	logWarn(vl.logger, msg, vl.with(keysAndValues)...)
+}
+
+func (vl valuesLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	vl.logger.Error(err, msg, vl.with(keysAndValues)...)
+}
+
+func (vl valuesLogger) WithValues(keysAndValues ...interface{}) Logger {
+	return valuesLogger{vl.logger, vl.with(keysAndValues)}
+}
+
+// logDebug logs at the debug level if the logger has one, at Info otherwise.
+func logDebug(l Logger, msg string, keysAndValues ...interface{}) {
+	if ll, ok := l.(LeveledLogger); ok {
+		ll.Debug(msg, keysAndValues...)
+		return
+	}
+	l.Info(msg, keysAndValues...)
+}
+
+// logWarn logs at the warn level if the logger has one, at Info otherwise.
+func logWarn(l Logger, msg string, keysAndValues ...interface{}) {
+	if ll, ok := l.(LeveledLogger); ok {
+		ll.Warn(msg, keysAndValues...)
+		return
+	}
+	l.Info(msg, keysAndValues...)
+}
+
+// logWith returns a logger adding the key/values to every line.
+func logWith(l Logger, keysAndValues ...interface{}) Logger {
+	if cl, ok := l.(ContextualLogger); ok {
+		return cl.WithValues(keysAndValues...)
+	}
+	return valuesLogger{l, keysAndValues}
 }
 
 // formatString returns a logfmt-like format string for the number of
@@ -0,0 +1,34 @@
+package scron
+
+import "github.com/go-logr/logr"
+
+// LogrLogger adapts a logr.Logger to the Logger interface. Debug logs at
+// verbosity 1, Warn at verbosity 0 with level=warn since logr has no warn
+// level.
+func LogrLogger(l logr.Logger) Logger {
+	return logrLogger{l}
+}
+
+type logrLogger struct {
+	logger logr.Logger
+}
+
+func (ll logrLogger) Debug(msg string, keysAndValues ...interface{}) {
+	ll.logger.V(1).Info(msg, keysAndValues...)
+}
+
+func (ll logrLogger) Info(msg string, keysAndValues ...interface{}) {
+	ll.logger.Info(msg, keysAndValues...)
+}
+
+func (ll logrLogger) Warn(msg string, keysAndValues ...interface{}) {
+	ll.logger.Info(msg, append([]interface{}{"level", "warn"}, keysAndValues...)...)
+}
+
+func (ll logrLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	ll.logger.Error(err, msg, keysAndValues...)
+}
+
+func (ll logrLogger) WithValues(keysAndValues ...interface{}) Logger {
+	return logrLogger{ll.logger.WithValues(keysAndValues...)}
+}
@@ -0,0 +1,38 @@
+//go:build go1.21
+
+package scron
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogLogger adapts a *slog.Logger to the Logger interface, with Debug and
+// Warn mapped to the slog levels and WithValues to With.
+func SlogLogger(l *slog.Logger) Logger {
+	return slogLogger{l}
+}
+
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+func (sl slogLogger) Debug(msg string, keysAndValues ...interface{}) {
+	sl.logger.Log(context.Background(), slog.LevelDebug, msg, keysAndValues...)
+}
+
+func (sl slogLogger) Info(msg string, keysAndValues ...interface{}) {
+	sl.logger.Log(context.Background(), slog.LevelInfo, msg, keysAndValues...)
+}
+
+func (sl slogLogger) Warn(msg string, keysAndValues ...interface{}) {
+	sl.logger.Log(context.Background(), slog.LevelWarn, msg, keysAndValues...)
+}
+
+func (sl slogLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	sl.logger.Log(context.Background(), slog.LevelError, msg, append([]interface{}{"error", err}, keysAndValues...)...)
+}
+
+func (sl slogLogger) WithValues(keysAndValues ...interface{}) Logger {
+	return slogLogger{sl.logger.With(keysAndValues...)}
+}
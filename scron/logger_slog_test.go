@@ -0,0 +1,48 @@
+//go:build go1.21
+
+package scron
+
+import (
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSlogLoggerEntryFields(t *testing.T) {
+	var buf syncBuffer
+	l := SlogLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	cron := New(WithParser(secondParser), WithChain(), WithLogger(l),
+		WithLockProvider(LocalLockProvider()))
+	cron.AddSingleton("@yearly", func() {}, "logged")
+	cron.Start()
+	defer cron.Stop()
+	if err := cron.Trigger("logged"); err != nil {
+		t.Fatal(err)
+	}
+	if !eventually(func() bool { return strings.Contains(buf.String(), `"msg":"job end"`) }) {
+		t.Fatalf("expected the end of the run in\n%s", buf.String())
+	}
+
+	levels := map[string]string{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var rec map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatal(err)
+		}
+		msg, _ := rec["msg"].(string)
+		levels[msg], _ = rec["level"].(string)
+		switch msg {
+		case "locked", "trigger", "job start", "job end":
+			if rec["entry"] != "logged" {
+				t.Errorf("expected the entry field in %s", line)
+			}
+		}
+		if strings.HasPrefix(msg, "job ") && rec["run"] == nil {
+			t.Errorf("expected the run field in %s", line)
+		}
+	}
+	if levels["locked"] != "DEBUG" || levels["trigger"] != "INFO" {
+		t.Errorf("unexpected levels %v", levels)
+	}
+}
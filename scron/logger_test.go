@@ -0,0 +1,142 @@
+package scron
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr/funcr"
+	"github.com/henryxu/tools/redis_locker"
+)
+
+func TestPrintfLoggerLevels(t *testing.T) {
+	var buf bytes.Buffer
+	l := LevelPrintfLogger(log.New(&buf, "", 0), LevelInfo)
+	logDebug(l, "debug")
+	l.Info("info", "k", 1)
+	logWarn(l, "warn")
+	l.Error(errors.New("boom"), "error")
+	want := "info, k=1\nwarn, level=warn\nerror, error=boom\n"
+	if buf.String() != want {
+		t.Errorf("expected %q, got %q", want, buf.String())
+	}
+
+	buf.Reset()
+	logWarn(PrintfLogger(log.New(&buf, "", 0)), "warn")
+	PrintfLogger(log.New(&buf, "", 0)).Info("info")
+	PrintfLogger(log.New(&buf, "", 0)).Error(errors.New("boom"), "error")
+	if buf.String() != "error, error=boom\n" {
+		t.Errorf("expected only the error, got %q", buf.String())
+	}
+}
+
+func TestLockFailedLevels(t *testing.T) {
+	var buf syncBuffer
+	cron := New(WithParser(secondParser), WithLockProvider(LocalLockProvider()),
+		WithLogger(LevelPrintfLogger(log.New(&buf, "", 0), LevelInfo)))
+	cron.AddSingleton("0 0 * * * ?", func() {}, "lock-levels")
+	e := cron.entries[0]
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if !cron.lock(e, at) {
+		t.Fatal("expected the first lock to succeed")
+	}
+
+	// Losing an activation to another node is routine.
+	if cron.lock(e, at) {
+		t.Fatal("expected the activation to be taken")
+	}
+	if buf.String() != "" {
+		t.Errorf("expected nothing logged above debug, got %q", buf.String())
+	}
+
+	// The previous run still holding the job is worth a warning.
+	if cron.lock(e, at.Add(time.Hour)) {
+		t.Fatal("expected the job to be still locked")
+	}
+	if !strings.Contains(buf.String(), "lock failed, level=warn") {
+		t.Errorf("expected a warning, got %q", buf.String())
+	}
+}
+
+// failingLockProvider fails every lock as an unreachable Redis would.
+type failingLockProvider struct{}
+
+func (failingLockProvider) Acquire(key, taskKey string, ttl int) (redis_locker.RedisLockInter, error) {
+	return nil, fmt.Errorf("failed to lock: %w", errors.New("connection refused"))
+}
+
+func TestLockErrorLoggedAtDefaultLevel(t *testing.T) {
+	var buf syncBuffer
+	cron := New(WithParser(secondParser), WithLockProvider(failingLockProvider{}),
+		WithLogger(PrintfLogger(log.New(&buf, "", 0))))
+	cron.AddSingleton("0 0 * * * ?", func() {}, "lock-error")
+	if cron.lock(cron.entries[0], time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatal("expected the lock to fail")
+	}
+	if !strings.Contains(buf.String(), "lock failed, error=failed to lock: connection refused") {
+		t.Errorf("expected the provider error logged, got %q", buf.String())
+	}
+}
+
+func TestLogWithPrependsValues(t *testing.T) {
+	var buf bytes.Buffer
+	l := logWith(logWith(VerbosePrintfLogger(log.New(&buf, "", 0)), "entry", "a"), "run", "1")
+	logDebug(l, "start", "k", "v")
+	l.Error(errors.New("boom"), "failed")
+	want := "start, entry=a, run=1, k=v\nfailed, error=boom, entry=a, run=1\n"
+	if buf.String() != want {
+		t.Errorf("expected %q, got %q", want, buf.String())
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe to write from the job goroutines.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestLogrLoggerEntryFields(t *testing.T) {
+	var buf syncBuffer
+	l := LogrLogger(funcr.New(func(prefix, args string) {
+		fmt.Fprintln(&buf, args)
+	}, funcr.Options{Verbosity: 1}))
+	cron := New(WithParser(secondParser), WithChain(), WithLogger(l),
+		WithLockProvider(LocalLockProvider()))
+	cron.AddContextJob("@yearly", FuncContextJob(func(context.Context) error { return nil }), "logged")
+	cron.Start()
+	defer cron.Stop()
+	if err := cron.Trigger("logged"); err != nil {
+		t.Fatal(err)
+	}
+
+	if !eventually(func() bool { return strings.Contains(buf.String(), `"msg"="job end"`) }) {
+		t.Fatalf("expected the end of the run in\n%s", buf.String())
+	}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if strings.Contains(line, `"msg"="job `) &&
+			(!strings.Contains(line, `"entry"="logged"`) || !strings.Contains(line, `"run"=`)) {
+			t.Errorf("expected entry and run fields in %s", line)
+		}
+		if strings.Contains(line, `"msg"="locked"`) && !strings.Contains(line, `"entry"="logged"`) {
+			t.Errorf("expected the entry field in %s", line)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package scron
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Reasons reported to Metrics.RunSkipped.
+const (
+	SkipPaused = "paused" // the entry is paused
+	SkipLocked = "locked" // another run holds the lock
+)
+
+// Metrics receives the measurements of a Cron, so that any backend can be
+// plugged in. Calls come from the scheduler and job goroutines and must not
+// block.
+type Metrics interface {
+	// RunFinished is called after every run with its duration and error.
+	RunFinished(entry string, duration time.Duration, err error)
+
+	// LockAcquired is called after every attempt to lock a run.
+	LockAcquired(entry string, acquired bool)
+
+	// RunSkipped is called when a due activation is not run, see Skip*.
+	RunSkipped(entry string, reason string)
+
+	// SchedulerLag is called when an entry fires, with how late it fired
+	// compared to Entry.Next.
+	SchedulerLag(entry string, lag time.Duration)
+}
+
+// discardMetrics is used by Cron if none is specified.
+type discardMetrics struct{}
+
+func (discardMetrics) RunFinished(string, time.Duration, error) {}
+func (discardMetrics) LockAcquired(string, bool)                {}
+func (discardMetrics) RunSkipped(string, string)                {}
+func (discardMetrics) SchedulerLag(string, time.Duration)       {}
+
+// DefaultDurationBuckets are the upper bounds, in seconds, of the run
+// duration histogram of PrometheusMetrics.
+var DefaultDurationBuckets = []float64{0.1, 0.5, 1, 5, 10, 30, 60, 300, 600, 1800, 3600}
+
+// PrometheusMetrics collects the metrics of a Cron and serves them in the
+// Prometheus text exposition format:
+//
+//	scron_runs_total{entry}                 counter
+//	scron_failures_total{entry}             counter
+//	scron_run_duration_seconds{entry}       histogram
+//	scron_lock_total{entry,result}          counter, result is acquired or failed
+//	scron_skipped_total{entry,reason}       counter
+//	scron_scheduler_lag_seconds{entry}      gauge, lag of the last activation
+type PrometheusMetrics struct {
+	mu        sync.Mutex
+	buckets   []float64
+	runs      map[string]float64
+	failures  map[string]float64
+	durations map[string]*histogram
+	locks     map[[2]string]float64
+	skipped   map[[2]string]float64
+	lag       map[string]float64
+}
+
+type histogram struct {
+	counts []float64 // per bucket, not cumulative
+	sum    float64
+	count  float64
+}
+
+// NewPrometheusMetrics returns an empty PrometheusMetrics. Buckets default to
+// DefaultDurationBuckets.
+func NewPrometheusMetrics(buckets ...float64) *PrometheusMetrics {
+	if len(buckets) == 0 {
+		buckets = DefaultDurationBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &PrometheusMetrics{
+		buckets:   buckets,
+		runs:      make(map[string]float64),
+		failures:  make(map[string]float64),
+		durations: make(map[string]*histogram),
+		locks:     make(map[[2]string]float64),
+		skipped:   make(map[[2]string]float64),
+		lag:       make(map[string]float64),
+	}
+}
+
+func (m *PrometheusMetrics) RunFinished(entry string, duration time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.runs[entry]++
+	if err != nil {
+		m.failures[entry]++
+	}
+	h := m.durations[entry]
+	if h == nil {
+		h = &histogram{counts: make([]float64, len(m.buckets))}
+		m.durations[entry] = h
+	}
+	seconds := duration.Seconds()
+	for i, le := range m.buckets {
+		if seconds <= le {
+			h.counts[i]++
+			break
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+func (m *PrometheusMetrics) LockAcquired(entry string, acquired bool) {
+	result := "failed"
+	if acquired {
+		result = "acquired"
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.locks[[2]string{entry, result}]++
+}
+
+func (m *PrometheusMetrics) RunSkipped(entry string, reason string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.skipped[[2]string{entry, reason}]++
+}
+
+func (m *PrometheusMetrics) SchedulerLag(entry string, lag time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lag[entry] = lag.Seconds()
+}
+
+// ServeHTTP writes the metrics, so that m can be mounted on a mux directly.
+func (m *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WriteTo(w)
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format.
+func (m *PrometheusMetrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var sb strings.Builder
+
+	writeHeader(&sb, "scron_runs_total", "counter", "Finished runs per entry.")
+	for _, entry := range sortedKeys(m.runs) {
+		writeSample(&sb, "scron_runs_total", m.runs[entry], "entry", entry)
+	}
+	writeHeader(&sb, "scron_failures_total", "counter", "Runs that returned an error or panicked.")
+	for _, entry := range sortedKeys(m.failures) {
+		writeSample(&sb, "scron_failures_total", m.failures[entry], "entry", entry)
+	}
+
+	writeHeader(&sb, "scron_run_duration_seconds", "histogram", "Duration of runs.")
+	entries := make([]string, 0, len(m.durations))
+	for entry := range m.durations {
+		entries = append(entries, entry)
+	}
+	sort.Strings(entries)
+	for _, entry := range entries {
+		h := m.durations[entry]
+		var cumulative float64
+		for i, le := range m.buckets {
+			cumulative += h.counts[i]
+			writeSample(&sb, "scron_run_duration_seconds_bucket", cumulative, "entry", entry, "le", formatFloat(le))
+		}
+		writeSample(&sb, "scron_run_duration_seconds_bucket", h.count, "entry", entry, "le", "+Inf")
+		writeSample(&sb, "scron_run_duration_seconds_sum", h.sum, "entry", entry)
+		writeSample(&sb, "scron_run_duration_seconds_count", h.count, "entry", entry)
+	}
+
+	writeHeader(&sb, "scron_lock_total", "counter", "Attempts to lock a run, by result.")
+	for _, key := range sortedPairs(m.locks) {
+		writeSample(&sb, "scron_lock_total", m.locks[key], "entry", key[0], "result", key[1])
+	}
+	writeHeader(&sb, "scron_skipped_total", "counter", "Due activations that were not run, by reason.")
+	for _, key := range sortedPairs(m.skipped) {
+		writeSample(&sb, "scron_skipped_total", m.skipped[key], "entry", key[0], "reason", key[1])
+	}
+	writeHeader(&sb, "scron_scheduler_lag_seconds", "gauge", "How late the last activation fired.")
+	for _, entry := range sortedKeys(m.lag) {
+		writeSample(&sb, "scron_scheduler_lag_seconds", m.lag[entry], "entry", entry)
+	}
+
+	n, err := io.WriteString(w, sb.String())
+	return int64(n), err
+}
+
+func writeHeader(sb *strings.Builder, name, kind, help string) {
+	fmt.Fprintf(sb, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// writeSample writes one sample line with the given label pairs.
+func writeSample(sb *strings.Builder, name string, value float64, labels ...string) {
+	sb.WriteString(name)
+	sb.WriteByte('{')
+	for i := 0; i < len(labels); i += 2 {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		fmt.Fprintf(sb, "%s=\"%s\"", labels[i], escapeLabel(labels[i+1]))
+	}
+	sb.WriteString("} ")
+	sb.WriteString(formatFloat(value))
+	sb.WriteByte('\n')
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(v string) string {
+	return labelEscaper.Replace(v)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func sortedKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedPairs(m map[[2]string]float64) [][2]string {
+	keys := make([][2]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	return keys
+}
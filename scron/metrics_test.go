@@ -0,0 +1,72 @@
+package scron
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrometheusMetricsExposition(t *testing.T) {
+	m := NewPrometheusMetrics(1, 10)
+	m.RunFinished("a", 500*time.Millisecond, nil)
+	m.RunFinished("a", 5*time.Second, errors.New("boom"))
+	m.RunFinished("a", time.Minute, nil)
+	m.LockAcquired("a", true)
+	m.LockAcquired("a", false)
+	m.RunSkipped("a", SkipLocked)
+	m.SchedulerLag("a", 1500*time.Millisecond)
+	m.RunFinished(`we"ird`, 0, nil)
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+	for _, line := range []string{
+		"# TYPE scron_runs_total counter",
+		`scron_runs_total{entry="a"} 3`,
+		`scron_failures_total{entry="a"} 1`,
+		"# TYPE scron_run_duration_seconds histogram",
+		`scron_run_duration_seconds_bucket{entry="a",le="1"} 1`,
+		`scron_run_duration_seconds_bucket{entry="a",le="10"} 2`,
+		`scron_run_duration_seconds_bucket{entry="a",le="+Inf"} 3`,
+		`scron_run_duration_seconds_sum{entry="a"} 65.5`,
+		`scron_run_duration_seconds_count{entry="a"} 3`,
+		`scron_lock_total{entry="a",result="acquired"} 1`,
+		`scron_lock_total{entry="a",result="failed"} 1`,
+		`scron_skipped_total{entry="a",reason="locked"} 1`,
+		`scron_scheduler_lag_seconds{entry="a"} 1.5`,
+		`scron_runs_total{entry="we\"ird"} 1`,
+	} {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("missing %q in\n%s", line, body)
+		}
+	}
+}
+
+func TestMetricsFromScheduler(t *testing.T) {
+	m := NewPrometheusMetrics()
+	locks := LocalLockProvider()
+	cron := New(WithParser(secondParser), WithChain(), WithLogger(DiscardLogger),
+		WithLockProvider(locks), WithMetrics(m))
+	cron.AddSingleton("* * * * * *", func() {}, "metered")
+	cron.AddSingleton("* * * * * *", func() {}, "paused")
+	cron.Pause("paused")
+	cron.Start()
+	defer cron.Stop()
+
+	var body string
+	for deadline := time.Now().Add(3 * time.Second); time.Now().Before(deadline); time.Sleep(50 * time.Millisecond) {
+		var sb strings.Builder
+		m.WriteTo(&sb)
+		body = sb.String()
+		if strings.Contains(body, `scron_runs_total{entry="metered"} `) &&
+			strings.Contains(body, `scron_lock_total{entry="metered",result="acquired"} `) &&
+			strings.Contains(body, `scron_skipped_total{entry="paused",reason="paused"} `) &&
+			strings.Contains(body, `scron_scheduler_lag_seconds{entry="metered"} `) {
+			return
+		}
+	}
+	t.Errorf("expected scheduler metrics, got\n%s", body)
+}
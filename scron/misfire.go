@@ -0,0 +1,213 @@
+package scron
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/henryxu/tools/common"
+)
+
+// MisfireMode says what to do with activations that were missed because no
+// node was running, or because the clock jumped forward.
+type MisfireMode int
+
+const (
+	// MisfireSkip drops missed activations. This is the default.
+	MisfireSkip MisfireMode = iota
+	// MisfireRunOnce runs the job once for all missed activations.
+	MisfireRunOnce
+	// MisfireRunAll runs the job for every missed activation, up to Limit.
+	MisfireRunAll
+)
+
+// defaultMisfireLimit caps MisfireRunAll when no Limit is given.
+const defaultMisfireLimit = 10
+
+// MisfirePolicy describes how an entry catches up on missed activations.
+type MisfirePolicy struct {
+	Mode MisfireMode
+
+	// Limit is the most missed activations run by MisfireRunAll, oldest
+	// first. Zero or less means 10.
+	Limit int
+}
+
+// limit returns how many missed activations may be run at once.
+func (p MisfirePolicy) limit() int {
+	switch p.Mode {
+	case MisfireRunOnce:
+		return 1
+	case MisfireRunAll:
+		if p.Limit <= 0 {
+			return defaultMisfireLimit
+		}
+		return p.Limit
+	}
+	return 0
+}
+
+// WithMisfire catches up on activations missed while the cluster was down, as
+// described by the policy. The last activation run is shared across nodes
+// through the Cron's RunStore (see WithRunStore), and catch-up runs take the
+// same lock as regular ones, so only one node runs them.
+func WithMisfire(policy MisfirePolicy) EntryOption {
+	return func(e *Entry) {
+		e.misfire = policy
+	}
+}
+
+// missedRuns returns up to limit activations of s after last and not after
+// now, oldest first.
+func missedRuns(s Schedule, last, now time.Time, limit int) []time.Time {
+	var missed []time.Time
+	for t := s.Next(last); len(missed) < limit && !t.IsZero() && !t.After(now); t = s.Next(t) {
+		missed = append(missed, t)
+	}
+	return missed
+}
+
+// RunStore remembers the last activation run by each job.
+type RunStore interface {
+	// LastRun returns the last activation run, or the zero time if unknown.
+	LastRun(name string) (time.Time, error)
+
+	// SetLastRun records the last activation run.
+	SetLastRun(name string, t time.Time) error
+}
+
+// memoryRunStore keeps the last runs in process memory.
+type memoryRunStore struct {
+	mu   sync.Mutex
+	runs map[string]time.Time
+}
+
+// MemoryRunStore returns a RunStore that only lives as long as the process,
+// for single-node services and tests.
+func MemoryRunStore() RunStore {
+	return &memoryRunStore{runs: make(map[string]time.Time)}
+}
+
+func (s *memoryRunStore) LastRun(name string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.runs[name], nil
+}
+
+func (s *memoryRunStore) SetLastRun(name string, t time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.runs[name] = t
+	return nil
+}
+
+// redisRunStore keeps the last runs in Redis, shared by the whole cluster.
+type redisRunStore struct {
+	client redis.UniversalClient
+}
+
+// RedisRunStore returns a RunStore backed by Redis. A nil client falls back to
+// common.NewRedisClient.
+func RedisRunStore(client redis.UniversalClient) RunStore {
+	return &redisRunStore{client: client}
+}
+
+// 获取key 任务名
+func lastRunKey(name string) string {
+	return fmt.Sprintf("last_%s", name)
+}
+
+func (s *redisRunStore) redis() redis.UniversalClient {
+	if s.client == nil {
+		return common.NewRedisClient()
+	}
+	return s.client
+}
+
+func (s *redisRunStore) LastRun(name string) (time.Time, error) {
+	value, err := s.redis().Get(context.Background(), lastRunKey(name)).Result()
+	if err == redis.Nil {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	ms, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if ms < legacyRunMillis {
+		// written in seconds by an earlier version
+		return time.Unix(ms, 0), nil
+	}
+	return time.UnixMilli(ms), nil
+}
+
+// legacyRunMillis separates last runs stored in seconds, all below it, from
+// those stored in milliseconds, all above it since 1973.
+const legacyRunMillis = 1e11
+
+// SetLastRun stores t in milliseconds, keeping the activations of sub-second
+// schedules such as FixedRate apart.
+func (s *redisRunStore) SetLastRun(name string, t time.Time) error {
+	return s.redis().Set(context.Background(), lastRunKey(name), t.UnixMilli(), 0).Err()
+}
+
+// defaultRunStore shares the last runs through Redis when the locks are taken
+// there, so that a Cron running without Redis does not need it for them.
+func defaultRunStore(locks LockProvider) RunStore {
+	if p, ok := locks.(*redisLockProvider); ok {
+		return RedisRunStore(p.client)
+	}
+	return MemoryRunStore()
+}
+
+// catchUp points the entry at the activations it missed since the last run
+// recorded in the RunStore, so that the scheduler runs them right away.
+func (c *Cron) catchUp(e *Entry, now time.Time) {
+	limit := e.misfire.limit()
+	if limit == 0 {
+		return
+	}
+	last, err := c.runs.LastRun(e.Name)
+	if err != nil {
+		e.logger.Error(err, "misfire")
+		return
+	}
+	if last.IsZero() {
+		return
+	}
+	missed := missedRuns(e.Schedule, last.In(now.Location()), now, limit)
+	if len(missed) == 0 {
+		return
+	}
+	e.logger.Info("misfire", "last", last, "missed", len(missed))
+	e.missed = missed[:len(missed)-1]
+	e.Next = missed[len(missed)-1]
+}
+
+// activations returns the activations of e to run now, oldest first: those
+// left over from catchUp, e.Next, and under MisfireRunAll any activations
+// skipped over by a clock jump.
+func (e *Entry) activations(now time.Time) []time.Time {
+	activations := append(e.missed, e.Next)
+	e.missed = nil
+	if e.misfire.Mode == MisfireRunAll {
+		limit := e.misfire.limit() - len(activations)
+		activations = append(activations, missedRuns(e.Schedule, e.Next, now, limit)...)
+	}
+	return activations
+}
+
+// markRun records the last activation claimed by this node.
+func (c *Cron) markRun(e *Entry, t time.Time) {
+	if e.misfire.Mode == MisfireSkip {
+		return
+	}
+	if err := c.runs.SetLastRun(e.Name, t); err != nil {
+		e.logger.Error(err, "misfire")
+	}
+}
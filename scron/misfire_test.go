@@ -0,0 +1,120 @@
+package scron
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+func TestMissedRuns(t *testing.T) {
+	sched, _ := secondParser.Parse("0 0 * * * ?")
+	last := getTime("Mon Jul 9 10:00 2012")
+	now := getTime("Mon Jul 9 13:30 2012")
+
+	missed := missedRuns(sched, last, now, 10)
+	expected := []string{"Mon Jul 9 11:00 2012", "Mon Jul 9 12:00 2012", "Mon Jul 9 13:00 2012"}
+	if len(missed) != len(expected) {
+		t.Fatalf("expected %d missed runs, got %v", len(expected), missed)
+	}
+	for i, e := range expected {
+		if !missed[i].Equal(getTime(e)) {
+			t.Errorf("missed run %d: expected %v, got %v", i, getTime(e), missed[i])
+		}
+	}
+
+	if missed := missedRuns(sched, last, now, 2); len(missed) != 2 || !missed[1].Equal(getTime(expected[1])) {
+		t.Errorf("expected the oldest 2 missed runs, got %v", missed)
+	}
+	if missed := missedRuns(sched, now, now, 10); len(missed) != 0 {
+		t.Errorf("expected no missed runs, got %v", missed)
+	}
+}
+
+func TestMisfire(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   MisfirePolicy
+		expected int
+	}{
+		{"skip", MisfirePolicy{Mode: MisfireSkip}, 0},
+		{"run once", MisfirePolicy{Mode: MisfireRunOnce}, 1},
+		{"run all", MisfirePolicy{Mode: MisfireRunAll, Limit: 3}, 3},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// The job was last run 10 seconds ago, every activation since was missed.
+			store := MemoryRunStore()
+			now := time.Now()
+			store.SetLastRun("misfire", now.Add(-10*time.Second))
+
+			results := make(chan Result, 20)
+			cron := New(WithParser(secondParser), WithLockProvider(NoopLockProvider()), WithLogger(DiscardLogger),
+				WithRunStore(store), WithResultHooks(func(r Result) { results <- r }))
+			cron.AddSingleton("* * * * * ?", func() {}, "misfire", WithMisfire(test.policy))
+			cron.Start()
+			time.Sleep(100 * time.Millisecond)
+			cron.Stop()
+
+			var caughtUp int
+			for len(results) > 0 {
+				if r := <-results; r.Scheduled.Before(now) {
+					caughtUp++
+				}
+			}
+			if caughtUp != test.expected {
+				t.Errorf("expected %d catch-up runs, got %d", test.expected, caughtUp)
+			}
+			if test.expected > 0 {
+				last, _ := store.LastRun("misfire")
+				if !last.After(now.Add(-10 * time.Second)) {
+					t.Errorf("expected last run to be recorded, got %v", last)
+				}
+			}
+		})
+	}
+}
+
+func TestRedisRunStore(t *testing.T) {
+	s := miniredis.RunT(t)
+	store := RedisRunStore(redis.NewClient(&redis.Options{Addr: s.Addr()}))
+
+	last, err := store.LastRun("job")
+	if err != nil || !last.IsZero() {
+		t.Errorf("expected zero time for unknown job, got %v, %v", last, err)
+	}
+	now := time.Date(2024, 1, 1, 0, 0, 1, 250*int(time.Millisecond), time.UTC)
+	if err := store.SetLastRun("job", now); err != nil {
+		t.Fatal(err)
+	}
+	if last, _ := store.LastRun("job"); !last.Equal(now) {
+		t.Errorf("expected %v, got %v", now, last)
+	}
+
+	// Runs stored in seconds are still read.
+	s.Set(lastRunKey("old"), strconv.FormatInt(now.Unix(), 10))
+	if last, _ := store.LastRun("old"); !last.Equal(now.Truncate(time.Second)) {
+		t.Errorf("expected %v, got %v", now.Truncate(time.Second), last)
+	}
+}
+
+func TestDefaultRunStore(t *testing.T) {
+	if _, ok := New().runs.(*redisRunStore); !ok {
+		t.Error("expected the runs kept in Redis with the Redis lock provider")
+	}
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"})
+	if store, ok := New(WithRedisClient(client)).runs.(*redisRunStore); !ok || store.client != client {
+		t.Error("expected the runs kept through the lock provider's client")
+	}
+	for _, locks := range []LockProvider{LocalLockProvider(), NoopLockProvider()} {
+		if _, ok := New(WithLockProvider(locks)).runs.(*memoryRunStore); !ok {
+			t.Errorf("expected the runs kept in memory with %T", locks)
+		}
+	}
+	store := MemoryRunStore()
+	if c := New(WithRunStore(store), WithRedisClient(client)); c.runs != store {
+		t.Error("expected WithRunStore to win")
+	}
+}
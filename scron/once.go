@@ -0,0 +1,78 @@
+package scron
+
+import "time"
+
+// OnceSchedule activates a single time, e.g. "At 2030-01-01 09:00".
+type OnceSchedule struct {
+	At time.Time
+}
+
+// OnceAt returns a Schedule that activates only at the given time. Its entry is
+// removed once it has run.
+func OnceAt(t time.Time) OnceSchedule {
+	return OnceSchedule{At: t}
+}
+
+// Next returns At if it is after t, the zero time otherwise.
+func (s OnceSchedule) Next(t time.Time) time.Time {
+	if s.At.After(t) {
+		return s.At
+	}
+	return time.Time{}
+}
+
+// BoundedSchedule limits a schedule to the activations between Start and End,
+// e.g. "Every hour from May 1 to May 7". A zero Start or End leaves that side
+// unbounded.
+type BoundedSchedule struct {
+	Schedule   Schedule
+	Start, End time.Time
+}
+
+// Bounded returns a Schedule that activates like schedule, neither before
+// start nor after end. Its entry is removed once end has passed.
+func Bounded(schedule Schedule, start, end time.Time) BoundedSchedule {
+	return BoundedSchedule{Schedule: schedule, Start: start, End: end}
+}
+
+// Unwrap returns the bounded schedule.
+func (s BoundedSchedule) Unwrap() Schedule {
+	return s.Schedule
+}
+
+// Next returns the next activation of the schedule within the bounds, or the
+// zero time if there is none left.
+func (s BoundedSchedule) Next(t time.Time) time.Time {
+	if t.Before(s.Start) {
+		t = s.Start.Add(-time.Second)
+	}
+	next := s.Schedule.Next(t)
+	for !next.IsZero() && next.Before(s.Start) {
+		next = s.Schedule.Next(next)
+	}
+	if !s.End.IsZero() && next.After(s.End) {
+		return time.Time{}
+	}
+	return next
+}
+
+// finite reports whether schedule runs out of activations by design: a
+// OnceSchedule or a BoundedSchedule with an End, possibly wrapped in
+// schedules with an Unwrap method such as Jitter.
+func finite(schedule Schedule) bool {
+	for {
+		switch s := schedule.(type) {
+		case OnceSchedule:
+			return true
+		case BoundedSchedule:
+			if !s.End.IsZero() {
+				return true
+			}
+			schedule = s.Schedule
+		case interface{ Unwrap() Schedule }:
+			schedule = s.Unwrap()
+		default:
+			return false
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package scron
+
+import (
+	"context"
+	"log"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOnceAt(t *testing.T) {
+	at := getTime("Mon Jul 9 15:00 2012")
+	s := OnceAt(at)
+	if next := s.Next(at.Add(-time.Minute)); !next.Equal(at) {
+		t.Errorf("expected %v, got %v", at, next)
+	}
+	if next := s.Next(at); !next.IsZero() {
+		t.Errorf("expected no activation after %v, got %v", at, next)
+	}
+}
+
+func TestBounded(t *testing.T) {
+	hourly, _ := ParseStandard("@hourly")
+	start := getTime("Mon Jul 9 15:00 2012")
+	end := getTime("Mon Jul 9 17:00 2012")
+	tests := []struct {
+		from     string
+		start    time.Time
+		end      time.Time
+		expected string
+	}{
+		{"Mon Jul 9 09:30 2012", start, end, "Mon Jul 9 15:00 2012"},
+		{"Mon Jul 9 15:00 2012", start, end, "Mon Jul 9 16:00 2012"},
+		{"Mon Jul 9 16:00 2012", start, end, "Mon Jul 9 17:00 2012"},
+		{"Mon Jul 9 17:00 2012", start, end, ""},
+		{"Mon Jul 9 09:30 2012", time.Time{}, end, "Mon Jul 9 10:00 2012"},
+		{"Mon Jul 9 20:30 2012", start, time.Time{}, "Mon Jul 9 21:00 2012"},
+	}
+	for _, test := range tests {
+		actual := Bounded(hourly, test.start, test.end).Next(getTime(test.from))
+		if expected := getTime(test.expected); !actual.Equal(expected) {
+			t.Errorf("from %s: expected %v, got %v", test.from, expected, actual)
+		}
+	}
+
+	// A start within a second is not activated before it.
+	every := Bounded(Every(time.Second), start.Add(1500*time.Millisecond), time.Time{})
+	if next := every.Next(start); next.Before(start.Add(1500 * time.Millisecond)) {
+		t.Errorf("expected an activation from the start, got %v", next)
+	}
+}
+
+func TestExhaustedEntryRemoved(t *testing.T) {
+	cron := New(WithChain(), WithLogger(DiscardLogger), WithLockProvider(LocalLockProvider()))
+	runs := make(chan struct{}, 1)
+	cron.Schedule(OnceAt(time.Now().Add(time.Second)), FuncJob(func() { runs <- struct{}{} }), "once")
+	cron.AddAfter([]string{"once"}, FuncJob(func() {}), "after")
+	cron.Start()
+	defer cron.Stop()
+
+	select {
+	case <-runs:
+	case <-time.After(2 * OneSecond):
+		t.Fatal("expected the job to run once")
+	}
+	if !eventually(func() bool { return len(cron.Entries()) == 1 }) {
+		t.Fatalf("expected the exhausted entry removed, got %d entries", len(cron.Entries()))
+	}
+	if _, err := cron.EntryByName("after"); err != nil {
+		t.Errorf("expected the dependent entry kept, got %v", err)
+	}
+}
+
+func TestExhaustedEntryKeptUntilRunCompletes(t *testing.T) {
+	cron := New(WithChain(), WithLogger(DiscardLogger), WithLockProvider(LocalLockProvider()))
+	done := make(chan error, 1)
+	cron.ScheduleContext(OnceAt(time.Now().Add(time.Second)), FuncContextJob(func(ctx context.Context) error {
+		select {
+		case <-ctx.Done():
+		case <-time.After(300 * time.Millisecond):
+		}
+		done <- ctx.Err()
+		return nil
+	}), "once")
+	cron.Start()
+	defer cron.Stop()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("expected the last run not to be interrupted, got %v", err)
+		}
+	case <-time.After(2 * OneSecond):
+		t.Fatal("expected the job to run once")
+	}
+	if !eventually(func() bool { return len(cron.Entries()) == 0 }) {
+		t.Fatalf("expected the exhausted entry removed, got %d entries", len(cron.Entries()))
+	}
+}
+
+func TestNeverMatchingEntryKept(t *testing.T) {
+	var buf syncBuffer
+	cron := New(WithChain(), WithLockProvider(LocalLockProvider()),
+		WithLogger(LevelPrintfLogger(log.New(&buf, "", 0), LevelWarn)))
+	cron.AddSingleton("0 0 30 2 *", func() {}, "february")
+	cron.Start()
+	defer cron.Stop()
+	cron.AddSingleton("@yearly", func() {}, "yearly")
+
+	if !eventually(func() bool { return len(cron.Entries()) == 2 }) {
+		t.Fatalf("expected the entry kept, got %d entries", len(cron.Entries()))
+	}
+	if n := strings.Count(buf.String(), "schedule never matches"); n != 1 {
+		t.Errorf("expected a single warning, got %q", buf.String())
+	}
+}
@@ -2,6 +2,8 @@ package scron
 
 import (
 	"time"
+
+	"github.com/go-redis/redis/v8"
 )
 
 // Option represents a modification to the default behavior of a Cron.
@@ -43,3 +45,119 @@ func WithLogger(logger Logger) Option {
 		c.logger = logger
 	}
 }
+
+// WithLockProvider overrides how runs are locked across nodes, e.g.
+// LocalLockProvider for single-node services or NoopLockProvider in tests.
+func WithLockProvider(p LockProvider) Option {
+	return func(c *Cron) {
+		c.locks = p
+	}
+}
+
+// WithRedisClient locks runs through the given Redis client instead of the
+// one registered in common. Server load reporting in sys_info still uses the
+// common registry.
+func WithRedisClient(client redis.UniversalClient) Option {
+	return WithLockProvider(RedisLockProvider(client))
+}
+
+// WithResultHooks calls the given hooks after every run, e.g. LogResult or
+// AlarmResult. Failed runs are always logged to the Cron's logger.
+func WithResultHooks(hooks ...ResultHook) Option {
+	return func(c *Cron) {
+		c.hooks = append(c.hooks, hooks...)
+	}
+}
+
+// WithHistory records every finished run in the given store, e.g.
+// RedisHistory for a history shared by the whole cluster. See Cron.History.
+func WithHistory(store HistoryStore) Option {
+	return func(c *Cron) {
+		c.history = store
+	}
+}
+
+// WithRunStore overrides where the last activation of each job is kept for
+// catching up on missed runs (see WithMisfire) and by AddAfter. Default:
+// RedisRunStore with the Redis lock provider, MemoryRunStore otherwise.
+func WithRunStore(store RunStore) Option {
+	return func(c *Cron) {
+		c.runs = store
+	}
+}
+
+// WithControlChannel makes the Cron apply the commands broadcast on the given
+// channel, e.g. RedisControlChannel, so that one Broadcast pauses, resumes,
+// triggers or removes an entry on every node. See Cron.Broadcast.
+func WithControlChannel(ch ControlChannel) Option {
+	return func(c *Cron) {
+		c.controlChannel = ch
+	}
+}
+
+// WithMetrics reports runs, locking and scheduler lag to m, e.g.
+// NewPrometheusMetrics.
+func WithMetrics(m Metrics) Option {
+	return func(c *Cron) {
+		c.metrics = m
+	}
+}
+
+// EntryOption represents a modification to the default behavior of a single
+// entry, passed when the job is added.
+type EntryOption func(*Entry)
+
+// withSpec records the spec string the entry's schedule was parsed from.
+func withSpec(spec string) EntryOption {
+	return func(e *Entry) {
+		e.Spec = spec
+	}
+}
+
+// withStatus sets the status the entry is added with, e.g. StatusStopped to
+// add it paused.
+func withStatus(status int) EntryOption {
+	return func(e *Entry) {
+		e.status = status
+	}
+}
+
+// WithWrappers wraps the job of this entry only, inside the Cron's chain, e.g.
+// with SkipIfStillRunning.
+func WithWrappers(wrappers ...JobWrapper) EntryOption {
+	return func(e *Entry) {
+		e.wrappers = append(e.wrappers, wrappers...)
+	}
+}
+
+// WithEntryLocation evaluates the schedule of this entry in loc, overriding the
+// Cron's location and any TZ prefix of the spec.
+func WithEntryLocation(loc *time.Location) EntryOption {
+	return func(e *Entry) {
+		e.location = loc
+	}
+}
+
+// WithDST selects how the schedule of this entry treats wall clock times
+// skipped or repeated by daylight saving transitions, see DSTPolicy.
+func WithDST(policy DSTPolicy) EntryOption {
+	return func(e *Entry) {
+		e.dst = policy
+	}
+}
+
+// WithJitter delays every activation of this entry by an offset below max
+// derived from its name, see Jitter.
+func WithJitter(max time.Duration) EntryOption {
+	return func(e *Entry) {
+		e.jitter = max
+	}
+}
+
+// WithJobTimeout cancels the context of a run once it has taken longer than d.
+// Only ContextJobs observe the cancellation.
+func WithJobTimeout(d time.Duration) EntryOption {
+	return func(e *Entry) {
+		e.timeout = d
+	}
+}
@@ -24,6 +24,8 @@ const (
 	Dow                                    // Day of week field, default *
 	DowOptional                            // Optional day of week field, default *
 	Descriptor                             // Allow descriptors such as @monthly, @weekly, etc.
+	Year                                   // Year field, default *
+	YearOptional                           // Optional year field, default *
 )
 
 var places = []ParseOption{
@@ -33,6 +35,7 @@ var places = []ParseOption{
 	Dom,
 	Month,
 	Dow,
+	Year,
 }
 
 var defaults = []string{
@@ -42,6 +45,7 @@ var defaults = []string{
 	"*",
 	"*",
 	"*",
+	"*",
 }
 
 // A custom Parser that can be configured.
@@ -67,6 +71,13 @@ type Parser struct {
 //	// Same as above, just makes Dow optional
 //	specParser := NewParser(Dom | Month | DowOptional)
 //	sched, err := specParser.Parse("15 */3")
+//
+//	// Seconds field, with an optional year field
+//	specParser := NewParser(Second | Minute | Hour | Dom | Month | Dow | YearOptional)
+//	sched, err := specParser.Parse("0 0 0 1 1 * 2030")
+//
+// Parsers without a Second or year field also accept a seconds field first and,
+// after it, a year field last, as in Quartz: "0 30 9 ? * MON-FRI 2025-2026".
 func NewParser(options ParseOption) Parser {
 	optionals := 0
 	if options&DowOptional > 0 {
@@ -75,6 +86,9 @@ func NewParser(options ParseOption) Parser {
 	if options&SecondOptional > 0 {
 		optionals++
 	}
+	if options&YearOptional > 0 {
+		optionals++
+	}
 	if optionals > 1 {
 		panic("multiple optionals may not be configured")
 	}
@@ -118,6 +132,31 @@ func (p Parser) Parse(spec string) (Schedule, error) {
 	if err != nil {
 		return nil, err
 	}
+	schedule, err := parseSpec(fields, loc, "")
+	if err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// parseSpec returns the schedule of the normalized fields, deriving their H
+// expressions from key.
+func parseSpec(fields []string, loc *time.Location, key string) (*SpecSchedule, error) {
+	var (
+		err        error
+		hashFields []string
+	)
+	resolved := make([]string, len(fields))
+	for i, f := range fields {
+		var hashed bool
+		if resolved[i], hashed, err = hashField(f, i, key); err != nil {
+			return nil, err
+		}
+		if hashed {
+			hashFields = fields
+		}
+	}
+	fields = resolved
 
 	field := func(field string, r bounds) uint64 {
 		if err != nil {
@@ -127,46 +166,76 @@ func (p Parser) Parse(spec string) (Schedule, error) {
 		bits, err = getField(field, r)
 		return bits
 	}
+	var days []dayExpr
+	dayField := func(field string, r bounds, ofWeek bool) uint64 {
+		if err != nil {
+			return 0
+		}
+		var (
+			bits  uint64
+			exprs []dayExpr
+		)
+		bits, exprs, err = getDayField(field, r, ofWeek)
+		days = append(days, exprs...)
+		return bits
+	}
 
 	var (
 		second     = field(fields[0], seconds)
 		minute     = field(fields[1], minutes)
 		hour       = field(fields[2], hours)
-		dayofmonth = field(fields[3], dom)
+		dayofmonth = dayField(fields[3], dom, false)
 		month      = field(fields[4], months)
-		dayofweek  = field(fields[5], dow)
+		dayofweek  = dayField(fields[5], dow, true)
 	)
 	if err != nil {
 		return nil, err
 	}
+	var year []yearRange
+	if len(fields) == len(places) {
+		if year, err = getYears(fields[6]); err != nil {
+			return nil, err
+		}
+	}
 
 	return &SpecSchedule{
-		Second:   second,
-		Minute:   minute,
-		Hour:     hour,
-		Dom:      dayofmonth,
-		Month:    month,
-		Dow:      dayofweek,
-		Location: loc,
+		Second:     second,
+		Minute:     minute,
+		Hour:       hour,
+		Dom:        dayofmonth,
+		Month:      month,
+		Dow:        dayofweek,
+		Location:   loc,
+		days:       days,
+		years:      year,
+		hashFields: hashFields,
 	}, nil
 }
 
 // normalizeFields takes a subset set of the time fields and returns the full set
-// with defaults (zeroes) populated for unset fields.
+// with defaults (zeroes) populated for unset fields. The year field is only
+// returned if configured or provided.
 //
 // As part of performing this function, it also validates that the provided
 // fields are compatible with the configured options.
 func normalizeFields(fields []string, options ParseOption) ([]string, error) {
 	// Validate optionals & add their field to options
 	optionals := 0
-	if len(fields) >= 6 {
+	if len(fields) >= 6 && options&Second == 0 && options&(Year|YearOptional) == 0 {
 		options |= Second
 		optionals++
+		if len(fields) == 7 {
+			options |= Year
+		}
 	}
 	if options&DowOptional > 0 {
 		options |= Dow
 		optionals++
 	}
+	if options&YearOptional > 0 {
+		options |= Year
+		optionals++
+	}
 	if optionals > 1 {
 		return nil, fmt.Errorf("multiple optionals may not be configured")
 	}
@@ -193,6 +262,8 @@ func normalizeFields(fields []string, options ParseOption) ([]string, error) {
 		switch {
 		case options&DowOptional > 0:
 			fields = append(fields, defaults[5]) // TODO: improve access to default
+		case options&YearOptional > 0:
+			fields = append(fields, defaults[6])
 		case options&SecondOptional > 0:
 			fields = append([]string{defaults[0]}, fields...)
 		default:
@@ -210,6 +281,9 @@ func normalizeFields(fields []string, options ParseOption) ([]string, error) {
 			n++
 		}
 	}
+	if options&Year == 0 {
+		expandedFields = expandedFields[:len(places)-1]
+	}
 	return expandedFields, nil
 }
 
@@ -252,6 +252,38 @@ func TestNormalizeFields(t *testing.T) {
 			SecondOptional | Hour | Dom | Month,
 			[]string{"0", "0", "5", "15", "*", "*"},
 		},
+		{
+			"AllFields_YearOptional_Provided",
+			[]string{"5", "*", "*", "*", "*", "2030"},
+			Minute | Hour | Dom | Month | Dow | YearOptional,
+			[]string{"0", "5", "*", "*", "*", "*", "2030"},
+		},
+		{
+			"AllFields_YearOptional_NotProvided",
+			[]string{"5", "*", "*", "*", "*"},
+			Minute | Hour | Dom | Month | Dow | YearOptional,
+			[]string{"0", "5", "*", "*", "*", "*", "*"},
+		},
+		{
+			"AllFields_SecondsAndYear_Quartz",
+			[]string{"0", "5", "*", "*", "*", "*", "2030"},
+			Minute | Hour | Dom | Month | Dow | Descriptor,
+			[]string{"0", "5", "*", "*", "*", "*", "2030"},
+		},
+		{
+			// A parser with a required seconds field is not made optional
+			// by the 6-field check, so Dow can still be the optional one.
+			"AllFields_Second_DowOptional_Provided",
+			[]string{"0", "5", "*", "*", "*", "1"},
+			Second | Minute | Hour | Dom | Month | DowOptional,
+			[]string{"0", "5", "*", "*", "*", "1"},
+		},
+		{
+			"AllFields_Second_DowOptional_NotProvided",
+			[]string{"0", "5", "*", "*", "*"},
+			Second | Minute | Hour | Dom | Month | DowOptional,
+			[]string{"0", "5", "*", "*", "*", "*"},
+		},
 	}
 
 	for _, test := range tests {
@@ -298,6 +330,18 @@ func TestNormalizeFields_Errors(t *testing.T) {
 			SecondOptional | Minute | Hour,
 			"",
 		},
+		{
+			"SecondsAndYearOptional",
+			[]string{"0", "5", "*", "*", "*", "*", "2030"},
+			Minute | Hour | Dom | Month | Dow | YearOptional,
+			"expected 5 to 6 fields",
+		},
+		{
+			"SecondRequired_NotProvided",
+			[]string{"5", "*", "*", "*", "*"},
+			Second | Minute | Hour | Dom | Month | Dow | Descriptor,
+			"expected exactly 6 fields",
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -320,7 +364,7 @@ func TestStandardSpecSchedule(t *testing.T) {
 	}{
 		{
 			expr:     "5 * * * *",
-			expected: &SpecSchedule{1 << seconds.min, 1 << 5, all(hours), all(dom), all(months), all(dow), time.Local},
+			expected: &SpecSchedule{Second: 1 << seconds.min, Minute: 1 << 5, Hour: all(hours), Dom: all(dom), Month: all(months), Dow: all(dow), Location: time.Local},
 		},
 		{
 			expr:     "@every 5m",
@@ -359,15 +403,15 @@ func TestNoDescriptorParser(t *testing.T) {
 }
 
 func every5min(loc *time.Location) *SpecSchedule {
-	return &SpecSchedule{1 << 0, 1 << 5, all(hours), all(dom), all(months), all(dow), loc}
+	return &SpecSchedule{Second: 1 << 0, Minute: 1 << 5, Hour: all(hours), Dom: all(dom), Month: all(months), Dow: all(dow), Location: loc}
 }
 
 func every5min5s(loc *time.Location) *SpecSchedule {
-	return &SpecSchedule{1 << 5, 1 << 5, all(hours), all(dom), all(months), all(dow), loc}
+	return &SpecSchedule{Second: 1 << 5, Minute: 1 << 5, Hour: all(hours), Dom: all(dom), Month: all(months), Dow: all(dow), Location: loc}
 }
 
 func midnight(loc *time.Location) *SpecSchedule {
-	return &SpecSchedule{1, 1, 1, all(dom), all(months), all(dow), loc}
+	return &SpecSchedule{Second: 1, Minute: 1, Hour: 1, Dom: all(dom), Month: all(months), Dow: all(dow), Location: loc}
 }
 
 func annual(loc *time.Location) *SpecSchedule {
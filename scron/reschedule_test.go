@@ -0,0 +1,72 @@
+package scron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRescheduleKeepsState(t *testing.T) {
+	cron := New(WithParser(secondParser), WithChain(), WithLogger(DiscardLogger),
+		WithLockProvider(LocalLockProvider()), WithHistory(MemoryHistory(10)))
+	runs := make(chan string, 10)
+	cron.AddSingleton("@yearly", func() { runs <- "old" }, "job")
+	cron.Start()
+	defer cron.Stop()
+
+	cron.Trigger("job")
+	<-runs
+	if !eventually(func() bool { return cron.Entry(1).Runs == 1 }) {
+		t.Fatal("expected the run to finish")
+	}
+	cron.Pause("job")
+
+	if err := cron.Reschedule("job", "@hourly"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cron.UpdateJob("job", FuncJob(func() { runs <- "new" })); err != nil {
+		t.Fatal(err)
+	}
+	e := cron.Entry(1)
+	if e.Spec != "@hourly" || e.status != StatusStopped || e.Prev.IsZero() || e.Runs != 1 {
+		t.Errorf("expected the entry state kept, got %+v", e)
+	}
+	if next := time.Until(e.Next); next <= 0 || next > time.Hour {
+		t.Errorf("expected the next run within an hour, got %v", e.Next)
+	}
+
+	// A trigger in the same second would contend for the same lock key, and
+	// the previous run releases its lock after finishing.
+	at := time.Now().Add(time.Hour)
+	if !eventually(func() bool {
+		return cron.sendControl(controlRequest{op: controlTrigger, name: "job", at: at}) == nil
+	}) {
+		t.Fatal("expected the trigger to take the lock")
+	}
+	if got := <-runs; got != "new" {
+		t.Errorf("expected the updated job to run, got %s", got)
+	}
+	if !eventually(func() bool {
+		records, _ := cron.History("job", 10)
+		return len(records) == 2
+	}) {
+		t.Error("expected the history kept across the update")
+	}
+}
+
+func TestRescheduleErrors(t *testing.T) {
+	cron := New(WithParser(secondParser), WithLogger(DiscardLogger), WithLockProvider(NoopLockProvider()))
+	cron.AddSingleton("@yearly", func() {}, "job")
+	if err := cron.Reschedule("missing", "@hourly"); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if err := cron.Reschedule("job", "bad spec"); err == nil {
+		t.Error("expected a parse error")
+	}
+	if err := cron.UpdateJob("missing", FuncJob(func() {})); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	// Before Start, the schedule is used when the Cron starts.
+	if err := cron.Reschedule("job", "@daily"); err != nil || cron.Entry(1).Spec != "@daily" {
+		t.Errorf("expected the entry rescheduled, got %v", err)
+	}
+}
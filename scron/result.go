@@ -0,0 +1,86 @@
+package scron
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/henryxu/tools/alarm"
+	"github.com/henryxu/tools/common"
+)
+
+// Result describes one finished run of an entry.
+type Result struct {
+	// Entry and Name identify the entry that ran.
+	Entry EntryID
+	Name  string
+
+	// RunID identifies the run in logs and traces, see RunInfo.
+	RunID string
+
+	// Scheduled is the activation time the run belongs to.
+	Scheduled time.Time
+
+	// Start and End bound the run.
+	Start, End time.Time
+
+	// Err is the error returned by the job, or the recovered panic.
+	Err error
+}
+
+// Duration returns how long the run took.
+func (r Result) Duration() time.Duration {
+	return r.End.Sub(r.Start)
+}
+
+// ResultHook is called after every run of every entry, from the goroutine that
+// ran the job. Hooks must not block for long.
+type ResultHook func(Result)
+
+// LogResult logs every run to the given logger, successes at Info and
+// failures at Error. The Cron logs failures at Error too, so given the Cron's
+// own logger a failure is logged twice.
+func LogResult(logger Logger) ResultHook {
+	return func(r Result) {
+		if r.Err != nil {
+			logger.Error(r.Err, "failed", "entry", r.Name, "scheduled", r.Scheduled, "duration", r.Duration())
+			return
+		}
+		logger.Info("finished", "entry", r.Name, "scheduled", r.Scheduled, "duration", r.Duration())
+	}
+}
+
+// AlarmResult sends failed runs to the given alarm, at most once every 5
+// minutes per entry. Like the lock alarms it only fires when common.RunMode is "prod".
+func AlarmResult(alarmIns alarm.IAlarm) ResultHook {
+	return func(r Result) {
+		if r.Err == nil || common.RunMode != "prod" {
+			return
+		}
+		alarmIns.SendAlarm(fmt.Sprintf("slp-tools.任务:%s,执行失败:%v,请及时处理！@all", r.Name, r.Err), "slp-tools.cron.fail:"+r.Name, 5*time.Minute)
+	}
+}
+
+// finish records the result on the entry and hands it to the hooks.
+func (c *Cron) finish(e *Entry, r Result) {
+	c.stateMu.Lock()
+	e.LastResult = r
+	e.Runs++
+	if r.Err != nil {
+		e.Failures++
+	}
+	c.stateMu.Unlock()
+
+	c.metrics.RunFinished(e.Name, r.Duration(), r.Err)
+	if r.Err != nil {
+		e.logger.Error(r.Err, "job failed", "run", r.RunID)
+	}
+	if c.history != nil {
+		if err := c.history.Add(newRecord(r)); err != nil {
+			e.logger.Error(err, "history", "run", r.RunID)
+		}
+	}
+	for _, hook := range c.hooks {
+		hook(r)
+	}
+	c.completed(e, r)
+}
@@ -0,0 +1,74 @@
+package scron
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResultHooks(t *testing.T) {
+	results := make(chan Result, 1)
+	failure := errors.New("boom")
+
+	cron := New(WithParser(secondParser), WithLockProvider(NoopLockProvider()), WithLogger(DiscardLogger),
+		WithResultHooks(func(r Result) { results <- r }))
+	cron.AddContextJob("* * * * * ?", FuncContextJob(func(ctx context.Context) error {
+		time.Sleep(10 * time.Millisecond)
+		return failure
+	}), "result-hook")
+	cron.Start()
+	defer cron.Stop()
+
+	var r Result
+	select {
+	case <-time.After(OneSecond):
+		t.Fatal("expected a result")
+	case r = <-results:
+	}
+	if r.Name != "result-hook" || r.Err != failure {
+		t.Errorf("unexpected result %+v", r)
+	}
+	if r.Duration() < 10*time.Millisecond {
+		t.Errorf("expected duration of at least 10ms, got %v", r.Duration())
+	}
+	if r.Scheduled.IsZero() || r.Start.Before(r.Scheduled) {
+		t.Errorf("expected start %v after scheduled %v", r.Start, r.Scheduled)
+	}
+
+	entry := cron.Entries()[0]
+	if entry.Runs != 1 || entry.Failures != 1 || entry.LastResult.Err != failure {
+		t.Errorf("expected entry to record the failed run, got runs=%d failures=%d err=%v",
+			entry.Runs, entry.Failures, entry.LastResult.Err)
+	}
+}
+
+func TestLogResult(t *testing.T) {
+	var buf bytes.Buffer
+	hook := LogResult(VerbosePrintfLogger(log.New(&buf, "", 0)))
+	hook(Result{Name: "ok"})
+	hook(Result{Name: "failed", Err: errors.New("boom")})
+	if out := buf.String(); !strings.Contains(out, "finished, entry=ok") {
+		t.Errorf("expected the successful run logged, got %q", out)
+	}
+
+	// A hook with an errors-only logger still records failures.
+	buf.Reset()
+	hook = LogResult(PrintfLogger(log.New(&buf, "", 0)))
+	hook(Result{Name: "ok"})
+	hook(Result{Name: "failed", Err: errors.New("boom")})
+	if out := buf.String(); strings.Contains(out, "entry=ok") || !strings.Contains(out, "failed, error=boom, entry=failed") {
+		t.Errorf("expected only the failed run logged, got %q", out)
+	}
+}
+
+func TestRecoverReportsPanic(t *testing.T) {
+	job := NewChain(Recover(DiscardLogger)).Then(FuncJob(func() { panic("oops") }))
+	err := runJob(context.Background(), job)
+	if err == nil || err.Error() != "oops" {
+		t.Errorf("expected panic to be returned as error, got %v", err)
+	}
+}
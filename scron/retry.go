@@ -0,0 +1,101 @@
+package scron
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy describes how a failed run is retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Values below 2 disable retries.
+	MaxAttempts int
+
+	// Backoff is the delay before the first retry. It doubles with every
+	// further attempt, up to MaxBackoff if that is set.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+
+	// Jitter shortens each delay by a random fraction of up to Jitter
+	// (between 0 and 1), so that failing nodes do not retry in lockstep.
+	Jitter float64
+
+	// Retryable reports whether err is worth retrying. A nil Retryable
+	// retries every error.
+	Retryable func(err error) bool
+}
+
+// delay returns the wait before the given retry, counting from 1.
+func (p RetryPolicy) delay(retry int) time.Duration {
+	d := p.Backoff
+	for i := 1; i < retry && (p.MaxBackoff <= 0 || d < p.MaxBackoff); i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if p.Jitter > 0 {
+		d -= time.Duration(p.Jitter * rand.Float64() * float64(d))
+	}
+	return d
+}
+
+// Retry runs the wrapped job again when it returns an error, as described by
+// the policy. Retries happen within the same run, so they stay on the node
+// holding the entry's lock, and stop once the next attempt would start after
+// the lock expires (see LockDeadline) or the run's context is done. Retries
+// are logged at Warn, with the run ID.
+func Retry(logger Logger, policy RetryPolicy) JobWrapper {
+	return func(j Job) Job {
+		return contextFuncJob(func(ctx context.Context) error {
+			var err error
+			log := logger
+			if info, ok := RunInfoFromContext(ctx); ok {
+				log = logWith(logger, "run", info.RunID)
+			}
+			for attempt := 1; ; attempt++ {
+				if err = runJob(ctx, j); err == nil {
+					return nil
+				}
+				if attempt >= policy.MaxAttempts {
+					return err
+				}
+				if policy.Retryable != nil && !policy.Retryable(err) {
+					return err
+				}
+				wait := policy.delay(attempt)
+				if deadline, ok := LockDeadline(ctx); ok && time.Now().Add(wait).After(deadline) {
+					logWarn(log, "retry abandoned", "attempt", attempt, "deadline", deadline, "error", err)
+					return err
+				}
+				logWarn(log, "retry", "attempt", attempt, "backoff", wait, "error", err)
+				timer := time.NewTimer(wait)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return err
+				case <-timer.C:
+				}
+			}
+		})
+	}
+}
+
+// WithRetry retries failed runs of the entry as described by the policy.
+// The retries wrap the configured Chain, so a panic recovered by Recover
+// counts as a failed attempt.
+func WithRetry(policy RetryPolicy) EntryOption {
+	return func(e *Entry) {
+		e.retry = &policy
+	}
+}
+
+type lockDeadlineKey struct{}
+
+// LockDeadline returns when the lock taken for the current run expires, as
+// derived from the gap to the entry's next activation.
+func LockDeadline(ctx context.Context) (time.Time, bool) {
+	deadline, ok := ctx.Value(lockDeadlineKey{}).(time.Time)
+	return deadline, ok
+}
@@ -0,0 +1,111 @@
+package scron
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func failingJob(calls *int, failures int, err error) Job {
+	return contextFuncJob(func(ctx context.Context) error {
+		*calls++
+		if *calls <= failures {
+			return err
+		}
+		return nil
+	})
+}
+
+func TestRetry(t *testing.T) {
+	failure := errors.New("boom")
+	policy := RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}
+
+	t.Run("succeeds after failures", func(t *testing.T) {
+		var calls int
+		err := runJob(context.Background(), Retry(DiscardLogger, policy)(failingJob(&calls, 2, failure)))
+		if err != nil || calls != 3 {
+			t.Errorf("expected success on 3rd attempt, got err=%v calls=%d", err, calls)
+		}
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		var calls int
+		err := runJob(context.Background(), Retry(DiscardLogger, policy)(failingJob(&calls, 5, failure)))
+		if err != failure || calls != 3 {
+			t.Errorf("expected failure after 3 attempts, got err=%v calls=%d", err, calls)
+		}
+	})
+
+	t.Run("does not retry unretryable errors", func(t *testing.T) {
+		var calls int
+		p := policy
+		p.Retryable = func(err error) bool { return err != failure }
+		err := runJob(context.Background(), Retry(DiscardLogger, p)(failingJob(&calls, 5, failure)))
+		if err != failure || calls != 1 {
+			t.Errorf("expected a single attempt, got err=%v calls=%d", err, calls)
+		}
+	})
+
+	t.Run("stops at the lock deadline", func(t *testing.T) {
+		var calls int
+		p := policy
+		p.Backoff = time.Second
+		ctx := context.WithValue(context.Background(), lockDeadlineKey{}, time.Now().Add(500*time.Millisecond))
+		err := runJob(ctx, Retry(DiscardLogger, p)(failingJob(&calls, 5, failure)))
+		if err != failure || calls != 1 {
+			t.Errorf("expected no retry past the lock deadline, got err=%v calls=%d", err, calls)
+		}
+	})
+
+	t.Run("stops when the context is done", func(t *testing.T) {
+		var calls int
+		p := policy
+		p.Backoff = time.Second
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		err := runJob(ctx, Retry(DiscardLogger, p)(failingJob(&calls, 5, failure)))
+		if err != failure || calls != 1 || time.Since(start) > 500*time.Millisecond {
+			t.Errorf("expected retry to stop with the context, got err=%v calls=%d", err, calls)
+		}
+	})
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	p := RetryPolicy{Backoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	expected := []time.Duration{100, 200, 400, 800, 1000, 1000}
+	for i, e := range expected {
+		if d := p.delay(i + 1); d != e*time.Millisecond {
+			t.Errorf("retry %d: expected %v, got %v", i+1, e*time.Millisecond, d)
+		}
+	}
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if d := p.delay(1); d < 50*time.Millisecond || d > 100*time.Millisecond {
+			t.Fatalf("expected jittered delay within [50ms, 100ms], got %v", d)
+		}
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	results := make(chan Result, 1)
+	var calls int
+
+	cron := New(WithParser(secondParser), WithLockProvider(NoopLockProvider()), WithLogger(DiscardLogger),
+		WithResultHooks(func(r Result) { results <- r }))
+	cron.AddJob("* * * * * ?", failingJob(&calls, 1, errors.New("boom")), "retry",
+		WithRetry(RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond}))
+	cron.Start()
+	defer cron.Stop()
+
+	select {
+	case <-time.After(OneSecond):
+		t.Fatal("expected a result")
+	case r := <-results:
+		if r.Err != nil || calls != 2 {
+			t.Errorf("expected retry to succeed, got err=%v calls=%d", r.Err, calls)
+		}
+	}
+}
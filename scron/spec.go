@@ -9,6 +9,20 @@ type SpecSchedule struct {
 
 	// Override location for this schedule.
 	Location *time.Location
+
+	// DST says how wall clock times skipped or repeated by daylight saving
+	// transitions in Location are treated.
+	DST DSTPolicy
+
+	// days holds the L, W and # expressions of the Dom and Dow fields.
+	days []dayExpr
+
+	// years holds the ranges of the year field, nil meaning every year.
+	years []yearRange
+
+	// hashFields holds the normalized fields of a spec with H expressions,
+	// derived again from the name of the entry using it.
+	hashFields []string
 }
 
 // bounds provides a range of acceptable values (plus a map of name to value).
@@ -56,6 +70,15 @@ const (
 // Next returns the next time this schedule is activated, greater than the given
 // time.  If no time can be found to satisfy the schedule, return the zero time.
 func (s *SpecSchedule) Next(t time.Time) time.Time {
+	if s.DST == (DSTPolicy{}) {
+		return s.next(t)
+	}
+	return s.nextDST(t)
+}
+
+// next returns the next activation time, skipping wall clock times in DST
+// gaps and activating twice in DST overlaps.
+func (s *SpecSchedule) next(t time.Time) time.Time {
 	// General approach
 	//
 	// For Month, Day, Hour, Minute, Second:
@@ -92,6 +115,19 @@ WRAP:
 		return time.Time{}
 	}
 
+	// Find the first applicable year, if restricted.
+	if s.years != nil {
+		year := nextYear(s.years, t.Year())
+		if year == 0 {
+			return time.Time{}
+		}
+		if year != t.Year() {
+			added = true
+			t = time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+			yearLimit = year + 5
+		}
+	}
+
 	// Find the first applicable month.
 	// If it's this month, then do nothing.
 	for 1<<uint(t.Month())&s.Month == 0 {
@@ -178,8 +214,8 @@ WRAP:
 // restrictions are satisfied by the given time.
 func dayMatches(s *SpecSchedule, t time.Time) bool {
 	var (
-		domMatch bool = 1<<uint(t.Day())&s.Dom > 0
-		dowMatch bool = 1<<uint(t.Weekday())&s.Dow > 0
+		domMatch bool = 1<<uint(t.Day())&s.Dom > 0 || matchDays(s.days, t, false)
+		dowMatch bool = 1<<uint(t.Weekday())&s.Dow > 0 || matchDays(s.days, t, true)
 	)
 	if s.Dom&starBit > 0 || s.Dow&starBit > 0 {
 		return domMatch && dowMatch
@@ -205,6 +205,19 @@ func TestErrors(t *testing.T) {
 		"60 0 * * *",
 		"0 60 * * *",
 		"0 0 * * XYZ",
+		"0 0 L-31 * *",
+		"0 0 32W * *",
+		"0 0 W * *",
+		"0 0 LX * *",
+		"0 0 * * 1#6",
+		"0 0 * * 8#1",
+		"0 0 * * 1#",
+		"0 0 * * XL",
+		"0 0 5L * *",
+		"0 0 0 * * * 1969",
+		"0 0 0 * * * 2100",
+		"0 0 0 * * * 2030-2025",
+		"0 0 0 * * * 2025/0",
 	}
 	for _, spec := range invalidSpecs {
 		_, err := ParseStandard(spec)
@@ -298,3 +311,91 @@ func TestSlash0NoHang(t *testing.T) {
 		t.Error("expected an error on 0 increment")
 	}
 }
+
+func TestNextDayExpressions(t *testing.T) {
+	runs := []struct {
+		time, spec string
+		expected   string
+	}{
+		// Last day of the month.
+		{"Mon Jan 1 00:00 2024", "0 0 L * *", "Wed Jan 31 00:00 2024"},
+		{"Wed Jan 31 00:00 2024", "0 0 L * *", "Thu Feb 29 00:00 2024"},
+		{"Wed Feb 1 00:00 2023", "0 0 L * ?", "Tue Feb 28 00:00 2023"},
+		{"Mon Jan 1 00:00 2024", "0 0 L-2 * *", "Mon Jan 29 00:00 2024"},
+		{"Sun Dec 31 12:00 2023", "0 0 1,L * *", "Mon Jan 1 00:00 2024"},
+		{"Mon Jan 1 00:01 2024", "0 0 1,L * *", "Wed Jan 31 00:00 2024"},
+
+		// Last business day: March 2024 ends on a Sunday, August 2024 on a Saturday.
+		{"Fri Mar 1 00:00 2024", "0 18 LW * *", "Fri Mar 29 18:00 2024"},
+		{"Thu Aug 1 00:00 2024", "0 18 LW * *", "Fri Aug 30 18:00 2024"},
+		{"Mon Apr 1 00:00 2024", "0 18 LW * *", "Tue Apr 30 18:00 2024"},
+
+		// Nearest weekday, within the month.
+		{"Sun Sep 1 00:00 2024", "0 9 15W * *", "Mon Sep 16 09:00 2024"},
+		{"Mon Jun 1 00:00 2024", "0 9 15W * *", "Fri Jun 14 09:00 2024"},
+		{"Mon Jun 1 00:00 2024", "0 9 1W * *", "Mon Jun 3 09:00 2024"},
+		{"Mon Jun 10 00:00 2024", "0 9 10W * *", "Mon Jun 10 09:00 2024"},
+		{"Tue Jun 1 00:00 2024", "0 9 30W * *", "Fri Jun 28 09:00 2024"},
+		{"Thu Feb 1 00:00 2024", "0 9 31W * *", "Fri Mar 29 09:00 2024"},
+
+		// Last weekday n of the month.
+		{"Mon Jan 1 00:00 2024", "0 0 * * 5L", "Fri Jan 26 00:00 2024"},
+		{"Mon Jan 1 00:00 2024", "0 0 ? * FRIL", "Fri Jan 26 00:00 2024"},
+		{"Sat Jan 27 00:00 2024", "0 0 * * 5L", "Fri Feb 23 00:00 2024"},
+		{"Mon Jan 1 00:00 2024", "0 0 * * L", "Sat Jan 6 00:00 2024"},
+
+		// n-th weekday of the month.
+		{"Mon Jan 1 00:00 2024", "0 10 * * 2#2", "Tue Jan 9 10:00 2024"},
+		{"Tue Jan 9 10:00 2024", "0 10 * * TUE#2", "Tue Feb 13 10:00 2024"},
+		{"Mon Jan 1 00:00 2024", "0 10 * * MON#1,FRI#3", "Mon Jan 1 10:00 2024"},
+		{"Mon Jan 1 10:00 2024", "0 10 * * MON#1,FRI#3", "Fri Jan 19 10:00 2024"},
+		{"Mon Jan 1 00:00 2024", "0 10 * * SUN#5", "Sun Mar 31 10:00 2024"},
+
+		// Combined with a plain day of week, only one needs to match.
+		{"Mon Jan 1 00:00 2024", "0 0 * * SUN,5L", "Sun Jan 7 00:00 2024"},
+		{"Sun Dec 31 12:00 2023", "0 0 L * MON", "Mon Jan 1 00:00 2024"},
+	}
+
+	for _, c := range runs {
+		sched, err := ParseStandard(c.spec)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		actual := sched.Next(getTime(c.time))
+		expected := getTime(c.expected)
+		if !actual.Equal(expected) {
+			t.Errorf("%s, \"%s\": (expected) %v != %v (actual)", c.time, c.spec, expected, actual)
+		}
+	}
+}
+
+func TestNextYear(t *testing.T) {
+	runs := []struct {
+		time, spec string
+		expected   string
+	}{
+		{"Mon Jan 1 00:00 2024", "0 0 9 1 1 * 2030", "Tue Jan 1 09:00 2030"},
+		{"Tue Jan 1 09:00 2030", "0 0 9 1 1 * 2030", ""},
+		{"Mon Jan 1 00:00 2024", "0 0 9 * * * 2024-2025", "Mon Jan 1 09:00 2024"},
+		{"Wed Dec 31 09:00 2025", "0 0 9 * * * 2024-2025", ""},
+		{"Mon Jan 1 00:00 2024", "0 0 0 29 2 * 2026/2", "Tue Feb 29 00:00 2028"},
+		{"Mon Jan 1 00:00 2024", "0 0 0 1 1 * 2026,2050", "Thu Jan 1 00:00 2026"},
+		{"Thu Jan 1 00:00 2026", "0 0 0 1 1 * 2026,2050", "Sat Jan 1 00:00 2050"},
+		{"Mon Jan 1 00:00 2024", "0 0 0 1 1 * *", "Wed Jan 1 00:00 2025"},
+		{"Mon Jan 1 00:00 2024", "0 0 0 31 2 * 2024-2099", ""},
+	}
+
+	for _, c := range runs {
+		sched, err := ParseStandard(c.spec)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		actual := sched.Next(getTime(c.time))
+		expected := getTime(c.expected)
+		if !actual.Equal(expected) {
+			t.Errorf("%s, \"%s\": (expected) %v != %v (actual)", c.time, c.spec, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package scron
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/henryxu/tools/sys_info"
+)
+
+// Span attribute keys set by Trace.
+const (
+	AttrEntryName = "scron.entry.name"
+	AttrScheduled = "scron.scheduled"
+	AttrRunID     = "scron.run.id"
+	AttrHost      = "scron.host"
+	AttrLockKey   = "scron.lock.key"
+)
+
+// Attribute is a key/value pair attached to a span.
+type Attribute struct {
+	Key   string
+	Value string
+}
+
+// Tracer starts spans, in the manner of an OpenTelemetry trace.Tracer. The
+// returned context carries the span so that spans started from it, e.g. by
+// the job for its downstream calls, are its children.
+type Tracer interface {
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+// Span is a traced unit of work.
+type Span interface {
+	// SetError marks the span as failed.
+	SetError(err error)
+	// End completes the span.
+	End()
+}
+
+// RunInfo describes the run a job is executing.
+type RunInfo struct {
+	Entry     EntryID
+	Name      string
+	RunID     string
+	Scheduled time.Time
+	// LockKey is the key locking the run in the cluster, see GetCronExecKey.
+	LockKey string
+	// FencingToken is issued by the lock of the run, see FencingToken.
+	FencingToken int64
+}
+
+type runInfoKey struct{}
+
+var runSeq uint64
+
+// newRunID returns an ID unique to this process for a run, ordered by start.
+func newRunID() string {
+	return fmt.Sprintf("%x-%d", time.Now().UnixNano(), atomic.AddUint64(&runSeq, 1))
+}
+
+// RunInfoFromContext returns the run the context passed to a ContextJob
+// belongs to.
+func RunInfoFromContext(ctx context.Context) (RunInfo, bool) {
+	info, ok := ctx.Value(runInfoKey{}).(RunInfo)
+	return info, ok
+}
+
+// Trace starts a span around every run, named after the entry and carrying
+// its name, scheduled time, host and lock key. ContextJobs receive the span
+// in their context.
+func Trace(tracer Tracer) JobWrapper {
+	return func(j Job) Job {
+		return contextFuncJob(func(ctx context.Context) (err error) {
+			info, _ := RunInfoFromContext(ctx)
+			ctx, span := tracer.Start(ctx, "scron "+info.Name,
+				Attribute{AttrEntryName, info.Name},
+				Attribute{AttrScheduled, info.Scheduled.Format(time.RFC3339)},
+				Attribute{AttrRunID, info.RunID},
+				Attribute{AttrHost, sys_info.LocalIP()},
+				Attribute{AttrLockKey, info.LockKey},
+			)
+			defer func() {
+				if r := recover(); r != nil {
+					span.SetError(fmt.Errorf("panic: %v", r))
+					span.End()
+					panic(r)
+				}
+				if err != nil {
+					span.SetError(err)
+				}
+				span.End()
+			}()
+			return runJob(ctx, j)
+		})
+	}
+}
+
+// SpanData is a span recorded by an InMemoryTracer.
+type SpanData struct {
+	Name       string
+	SpanID     string
+	ParentID   string
+	Attributes map[string]string
+	Start, End time.Time
+	Err        error
+}
+
+// InMemoryTracer keeps the spans it started, for tests.
+type InMemoryTracer struct {
+	mu     sync.Mutex
+	nextID uint64
+	spans  []*SpanData
+}
+
+// NewInMemoryTracer returns an empty InMemoryTracer.
+func NewInMemoryTracer() *InMemoryTracer {
+	return &InMemoryTracer{}
+}
+
+type memorySpanKey struct{}
+
+func (t *InMemoryTracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.nextID++
+	data := &SpanData{
+		Name:       name,
+		SpanID:     strconv.FormatUint(t.nextID, 16),
+		Attributes: make(map[string]string, len(attrs)),
+		Start:      time.Now(),
+	}
+	if parent, ok := ctx.Value(memorySpanKey{}).(*SpanData); ok {
+		data.ParentID = parent.SpanID
+	}
+	for _, attr := range attrs {
+		data.Attributes[attr.Key] = attr.Value
+	}
+	t.spans = append(t.spans, data)
+	return context.WithValue(ctx, memorySpanKey{}, data), &memorySpan{tracer: t, data: data}
+}
+
+// Spans returns the ended spans in the order they were started.
+func (t *InMemoryTracer) Spans() []SpanData {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var spans []SpanData
+	for _, span := range t.spans {
+		if !span.End.IsZero() {
+			spans = append(spans, *span)
+		}
+	}
+	return spans
+}
+
+type memorySpan struct {
+	tracer *InMemoryTracer
+	data   *SpanData
+}
+
+func (s *memorySpan) SetError(err error) {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	s.data.Err = err
+}
+
+func (s *memorySpan) End() {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	if s.data.End.IsZero() {
+		s.data.End = time.Now()
+	}
+}
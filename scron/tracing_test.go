@@ -0,0 +1,69 @@
+package scron
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTraceSpanPerRun(t *testing.T) {
+	tracer := NewInMemoryTracer()
+	cron := New(WithParser(secondParser), WithChain(Recover(DiscardLogger), Trace(tracer)),
+		WithLogger(DiscardLogger), WithLockProvider(LocalLockProvider()))
+	cron.AddContextJob("@yearly", FuncContextJob(func(ctx context.Context) error {
+		_, span := tracer.Start(ctx, "downstream")
+		span.End()
+		return errors.New("boom")
+	}), "traced")
+	cron.Start()
+	defer cron.Stop()
+
+	at := time.Date(2030, 1, 2, 3, 4, 5, 0, time.Local)
+	if err := cron.sendControl(controlRequest{op: controlTrigger, name: "traced", at: at}); err != nil {
+		t.Fatal(err)
+	}
+	var spans []SpanData
+	if !eventually(func() bool {
+		spans = tracer.Spans()
+		return len(spans) == 2
+	}) {
+		t.Fatalf("expected 2 spans, got %+v", spans)
+	}
+
+	child, run := spans[1], spans[0]
+	if child.Name != "downstream" {
+		child, run = spans[0], spans[1]
+	}
+	if run.Name != "scron traced" || run.Err == nil || run.ParentID != "" {
+		t.Errorf("unexpected run span %+v", run)
+	}
+	if child.ParentID != run.SpanID {
+		t.Errorf("expected the downstream span to be a child of the run, got %+v", child)
+	}
+	want := map[string]string{
+		AttrEntryName: "traced",
+		AttrScheduled: at.Format(time.RFC3339),
+		AttrLockKey:   "cron_traced20300102030405",
+	}
+	for k, v := range want {
+		if run.Attributes[k] != v {
+			t.Errorf("expected %s=%q, got %q", k, v, run.Attributes[k])
+		}
+	}
+	if _, ok := run.Attributes[AttrHost]; !ok {
+		t.Error("expected the host attribute")
+	}
+}
+
+func TestTraceRecordsPanic(t *testing.T) {
+	tracer := NewInMemoryTracer()
+	job := NewChain(Recover(DiscardLogger), Trace(tracer)).Then(FuncJob(func() { panic("oops") }))
+	if err := runJob(context.Background(), job); err == nil {
+		t.Error("expected the panic as an error")
+	}
+	spans := tracer.Spans()
+	if len(spans) != 1 || spans[0].Err == nil {
+		t.Errorf("expected a failed span, got %+v", spans)
+	}
+}
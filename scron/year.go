@@ -0,0 +1,104 @@
+package scron
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The bounds of the year field, which does not fit in bits.
+var years = bounds{1970, 2099, nil}
+
+// yearRange is one range of the year field: start-end/step.
+type yearRange struct {
+	start, end, step uint
+}
+
+// getYears returns the ranges of the year field, or nil if it matches every
+// year.
+func getYears(field string) ([]yearRange, error) {
+	var ranges []yearRange
+	for _, expr := range strings.FieldsFunc(field, func(r rune) bool { return r == ',' }) {
+		r, err := getYearRange(expr)
+		if err != nil {
+			return nil, err
+		}
+		if r == (yearRange{years.min, years.max, 1}) {
+			return nil, nil
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges, nil
+}
+
+// getYearRange parses expr like getRange does, for the year field.
+func getYearRange(expr string) (yearRange, error) {
+	var (
+		r            = yearRange{step: 1}
+		rangeAndStep = strings.Split(expr, "/")
+		lowAndHigh   = strings.Split(rangeAndStep[0], "-")
+		err          error
+	)
+	if lowAndHigh[0] == "*" || lowAndHigh[0] == "?" {
+		r.start, r.end = years.min, years.max
+	} else {
+		if r.start, err = mustParseInt(lowAndHigh[0]); err != nil {
+			return r, err
+		}
+		switch len(lowAndHigh) {
+		case 1:
+			r.end = r.start
+		case 2:
+			if r.end, err = mustParseInt(lowAndHigh[1]); err != nil {
+				return r, err
+			}
+		default:
+			return r, fmt.Errorf("too many hyphens: %s", expr)
+		}
+	}
+
+	switch len(rangeAndStep) {
+	case 1:
+	case 2:
+		if r.step, err = mustParseInt(rangeAndStep[1]); err != nil {
+			return r, err
+		}
+		// Special handling: "N/step" means "N-max/step".
+		if len(lowAndHigh) == 1 {
+			r.end = years.max
+		}
+	default:
+		return r, fmt.Errorf("too many slashes: %s", expr)
+	}
+
+	if r.start < years.min {
+		return r, fmt.Errorf("beginning of range (%d) below minimum (%d): %s", r.start, years.min, expr)
+	}
+	if r.end > years.max {
+		return r, fmt.Errorf("end of range (%d) above maximum (%d): %s", r.end, years.max, expr)
+	}
+	if r.start > r.end {
+		return r, fmt.Errorf("beginning of range (%d) beyond end of range (%d): %s", r.start, r.end, expr)
+	}
+	if r.step == 0 {
+		return r, fmt.Errorf("step of range should be a positive number: %s", expr)
+	}
+	return r, nil
+}
+
+// nextYear returns the first year from the given one matched by the ranges,
+// or 0 if there is none.
+func nextYear(ranges []yearRange, year int) int {
+	next := 0
+	for _, r := range ranges {
+		y := uint(year)
+		if y < r.start {
+			y = r.start
+		} else if rem := (y - r.start) % r.step; rem > 0 {
+			y += r.step - rem
+		}
+		if y <= r.end && (next == 0 || int(y) < next) {
+			next = int(y)
+		}
+	}
+	return next
+}
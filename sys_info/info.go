@@ -3,6 +3,7 @@ package sys_info
 import (
 	"log"
 	"net"
+	"sync"
 	"time"
 
 	"github.com/shirou/gopsutil/cpu"
@@ -61,6 +62,19 @@ func getLocalIP() string {
 	return ""
 }
 
+var (
+	localIP     string
+	localIPOnce sync.Once
+)
+
+// LocalIP 本机eth0的IPv4地址，只获取一次
+func LocalIP() string {
+	localIPOnce.Do(func() {
+		localIP = getLocalIP()
+	})
+	return localIP
+}
+
 func GetSysInfo() *SysInfo {
 	return &SysInfo{
 		Ip:     getLocalIP(),